@@ -0,0 +1,179 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrSourceUnavailable is reported for a source whose circuit breaker is open
+var ErrSourceUnavailable = errors.New("source unavailable: circuit breaker open")
+
+// BreakerState is the state of the circuit breaker of a source
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerPolicy controls when the circuit breaker of a source trips and recovers
+type BreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failed calls that opens the breaker, 0 disables breakers
+	FailureThreshold int
+	// Cooldown is how long an open breaker short-circuits calls before a single probe call is let through
+	Cooldown time.Duration
+}
+
+// BreakerStatus is a snapshot of the circuit breaker of one source
+type BreakerStatus struct {
+	Source              string
+	State               BreakerState
+	ConsecutiveFailures int
+	// OpenedAt is when the breaker last opened, zero if it never did
+	OpenedAt time.Time
+}
+
+// DefaultBreakerPolicy opens a breaker after 5 consecutive failures and probes again after 30 seconds
+func DefaultBreakerPolicy() BreakerPolicy {
+	return BreakerPolicy{
+		FailureThreshold: 5,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+// WithBreakerPolicy replaces DefaultBreakerPolicy for every source
+func WithBreakerPolicy(policy BreakerPolicy) Option {
+	return func(f *fetcher) {
+		f.breakerPolicy = policy
+	}
+}
+
+type breakerOutcome int
+
+const (
+	breakerSuccess breakerOutcome = iota
+	breakerFailure
+	// breakerNeutral outcomes say nothing about the upstream health, e.g. the caller gave up
+	breakerNeutral
+)
+
+type circuitBreaker struct {
+	mu       sync.Mutex
+	policy   BreakerPolicy
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// allow reports whether a call may go through, in the half-open state only one probe is let through at a time
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.policy.Cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) record(outcome breakerOutcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+	}
+	switch outcome {
+	case breakerSuccess:
+		b.state = BreakerClosed
+		b.failures = 0
+	case breakerFailure:
+		b.failures++
+		if b.state == BreakerHalfOpen || b.failures >= b.policy.FailureThreshold {
+			b.state = BreakerOpen
+			b.openedAt = time.Now()
+		}
+	}
+}
+
+func (b *circuitBreaker) status(source string) BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BreakerStatus{
+		Source:              source,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		OpenedAt:            b.openedAt,
+	}
+}
+
+// breaker returns the circuit breaker of source, nil when breakers are disabled
+func (f *fetcher) breaker(source string) *circuitBreaker {
+	if f.breakerPolicy.FailureThreshold <= 0 {
+		return nil
+	}
+	f.breakerMu.Lock()
+	defer f.breakerMu.Unlock()
+
+	b, ok := f.breakers[source]
+	if !ok {
+		b = &circuitBreaker{policy: f.breakerPolicy, state: BreakerClosed}
+		f.breakers[source] = b
+	}
+	return b
+}
+
+// breakerOutcomeOf decides whether err is a sign of the upstream being down
+// only network failures, timeouts not caused by the caller, 429 and 5xx responses count against a source
+func breakerOutcomeOf(ctx context.Context, err error) breakerOutcome {
+	if err == nil {
+		return breakerSuccess
+	}
+	if ctx.Err() != nil {
+		return breakerNeutral
+	}
+
+	switch classifyError(err) {
+	case ErrKindNetwork, ErrKindTimeout:
+		return breakerFailure
+	case ErrKindHTTP:
+		var httpErr *HTTPError
+		errors.As(err, &httpErr)
+		if httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError {
+			return breakerFailure
+		}
+	}
+	return breakerNeutral
+}
+
+// BreakerStates reports the circuit breaker of every source queried so far, sorted by source
+func (f *fetcher) BreakerStates() []BreakerStatus {
+	f.breakerMu.Lock()
+	defer f.breakerMu.Unlock()
+
+	var states []BreakerStatus
+	for source, b := range f.breakers {
+		states = append(states, b.status(source))
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Source < states[j].Source })
+	return states
+}
@@ -0,0 +1,106 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	srv := newTestServer(t)
+	var calls, healthy int32
+	srv.Handle("/api/v2/user", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"result": {"username": "brantly"}}`))
+	})
+	f := newTestFetcher(t, srv,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+		WithBreakerPolicy(BreakerPolicy{FailureThreshold: 2, Cooldown: 100 * time.Millisecond}),
+		onlySources(SUPERRARE),
+	)
+
+	for i := 0; i < 2; i++ {
+		ids, _ := f.FetchIdentity(testAddress)
+		if s := statusOf(t, ids.Status, SUPERRARE); s.ErrKind != ErrKindHTTP {
+			t.Fatalf("call %d: status = %+v, want HTTP error", i, s)
+		}
+	}
+	if states := f.BreakerStates(); len(states) != 1 || states[0].State != BreakerOpen || states[0].ConsecutiveFailures != 2 {
+		t.Fatalf("BreakerStates() = %+v, want Superrare open", states)
+	}
+
+	ids, _ := f.FetchIdentity(testAddress)
+	s := statusOf(t, ids.Status, SUPERRARE)
+	if s.ErrKind != ErrKindUnavailable || !errors.Is(s.Err, ErrSourceUnavailable) || calls != 2 {
+		t.Errorf("status = %+v after %d upstream calls, want short-circuited", s, calls)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	atomic.StoreInt32(&healthy, 1)
+	ids, _ = f.FetchIdentity(testAddress)
+	if s := statusOf(t, ids.Status, SUPERRARE); s.State != SourceSuccess {
+		t.Errorf("probe status = %+v, want success", s)
+	}
+	if states := f.BreakerStates(); states[0].State != BreakerClosed || states[0].ConsecutiveFailures != 0 {
+		t.Errorf("BreakerStates() = %+v, want Superrare closed", states)
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	b := &circuitBreaker{policy: BreakerPolicy{FailureThreshold: 1, Cooldown: 10 * time.Millisecond}, state: BreakerClosed}
+	b.record(breakerFailure)
+	if b.allow() {
+		t.Fatal("open breaker let a call through")
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("breaker did not let a probe through after the cooldown")
+	}
+	if b.allow() {
+		t.Fatal("half-open breaker let a second probe through")
+	}
+	b.record(breakerFailure)
+	if s := b.status(SUPERRARE); s.State != BreakerOpen {
+		t.Errorf("failed probe left the breaker %s", s.State)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	b.allow()
+	b.record(breakerNeutral)
+	if !b.allow() {
+		t.Error("a neutral probe outcome did not free the probe slot")
+	}
+}
+
+func TestBreakerOutcome(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		err  error
+		want breakerOutcome
+	}{
+		{nil, breakerSuccess},
+		{&HTTPError{StatusCode: http.StatusBadGateway}, breakerFailure},
+		{&HTTPError{StatusCode: http.StatusTooManyRequests}, breakerFailure},
+		{&HTTPError{StatusCode: http.StatusNotFound}, breakerNeutral},
+		{errors.New("boom"), breakerNeutral},
+	}
+	for _, tt := range tests {
+		if got := breakerOutcomeOf(ctx, tt.err); got != tt.want {
+			t.Errorf("breakerOutcomeOf(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if got := breakerOutcomeOf(canceled, canceled.Err()); got != breakerNeutral {
+		t.Errorf("a caller cancellation counted as %v", got)
+	}
+}
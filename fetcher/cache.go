@@ -0,0 +1,270 @@
+package fetcher
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// cacheRefreshTimeout bounds the background refresh of a stale cache entry
+const cacheRefreshTimeout = 30 * time.Second
+
+// Cache stores the JSON encoded result of a source for an address, implementations must be safe for concurrent use
+// entries are never considered expired by the cache itself, freshness is decided by the fetcher from StoredAt
+type Cache interface {
+	Get(key string) (CacheItem, bool)
+	Set(key string, item CacheItem)
+}
+
+// CacheItem is one cached source result
+type CacheItem struct {
+	Value    []byte
+	StoredAt time.Time
+	// Negative marks a result in which the source found nothing for the address
+	Negative bool
+}
+
+// CacheTTL controls how long the results of a source are served from the cache
+// results of sources registered outside this package lose the concrete type of IdentityEntry.Custom
+// when they go through a cache that encodes them, such as DiskCache
+type CacheTTL struct {
+	// TTL is how long a result stays fresh, 0 disables caching
+	TTL time.Duration
+	// NegativeTTL is how long a result without data stays fresh, 0 disables negative caching
+	NegativeTTL time.Duration
+	// StaleTTL is how long past its freshness a result is still served while it is refreshed in the background
+	StaleTTL time.Duration
+}
+
+// WithCache serves the results of every source from cache according to ttl
+func WithCache(cache Cache, ttl CacheTTL) Option {
+	return func(f *fetcher) {
+		f.cache = cache
+		f.cacheTTL = ttl
+	}
+}
+
+// WithSourceCacheTTL overrides the TTLs given to WithCache for source
+func WithSourceCacheTTL(source string, ttl CacheTTL) Option {
+	return func(f *fetcher) {
+		f.sourceCacheTTL[source] = ttl
+	}
+}
+
+type cacheState int
+
+const (
+	cacheMiss cacheState = iota
+	cacheFresh
+	cacheStale
+)
+
+func cacheKey(kind, source, address string) string {
+	return kind + "/" + source + "/" + strings.ToLower(address)
+}
+
+func (f *fetcher) ttlOf(source string) CacheTTL {
+	if ttl, ok := f.sourceCacheTTL[source]; ok {
+		return ttl
+	}
+	return f.cacheTTL
+}
+
+// cacheGet decodes the entry of key into out and reports whether it is fresh or stale
+func (f *fetcher) cacheGet(source, key string, out interface{}) cacheState {
+	if f.cache == nil {
+		return cacheMiss
+	}
+	item, ok := f.cache.Get(key)
+	if !ok {
+		return cacheMiss
+	}
+
+	ttl := f.ttlOf(source)
+	fresh := ttl.TTL
+	if item.Negative {
+		fresh = ttl.NegativeTTL
+	}
+	age := time.Since(item.StoredAt)
+	if fresh <= 0 || age > fresh+ttl.StaleTTL {
+		return cacheMiss
+	}
+	if err := json.Unmarshal(item.Value, out); err != nil {
+		zap.L().With(zap.Error(err), zap.String("key", key)).Warn("cache entry json unmarshal failed")
+		return cacheMiss
+	}
+	if age > fresh {
+		return cacheStale
+	}
+	return cacheFresh
+}
+
+func (f *fetcher) cacheSet(source, key string, value interface{}, negative bool) {
+	if f.cache == nil {
+		return
+	}
+	ttl := f.ttlOf(source)
+	if ttl.TTL <= 0 || (negative && ttl.NegativeTTL <= 0) {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("key", key)).Warn("cache entry json marshal failed")
+		return
+	}
+	f.cache.Set(key, CacheItem{Value: data, StoredAt: time.Now(), Negative: negative})
+}
+
+// refreshInBackground runs refresh once per key at a time, detached from the caller's context
+func (f *fetcher) refreshInBackground(key string, refresh func(ctx context.Context)) {
+	f.refreshMu.Lock()
+	if f.refreshing[key] {
+		f.refreshMu.Unlock()
+		return
+	}
+	f.refreshing[key] = true
+	f.refreshMu.Unlock()
+
+	go func() {
+		defer func() {
+			f.refreshMu.Lock()
+			delete(f.refreshing, key)
+			f.refreshMu.Unlock()
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), cacheRefreshTimeout)
+		defer cancel()
+		refresh(withRequestStats(ctx, &requestStats{}))
+	}()
+}
+
+// LRUCache is an in-memory Cache holding at most a fixed number of entries
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key  string
+	item CacheItem
+}
+
+// NewLRUCache creates an LRUCache evicting the least recently used entry beyond capacity entries
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(key string) (CacheItem, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return CacheItem{}, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).item, true
+}
+
+func (c *LRUCache) Set(key string, item CacheItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*lruEntry).item = item
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, item: item})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of entries held
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// DiskCache is a Cache keeping one JSON file per entry in a directory, so entries survive restarts
+// entries stay on disk until they are overwritten or the directory is cleaned up
+type DiskCache struct {
+	dir string
+}
+
+type diskCacheFile struct {
+	Key      string          `json:"key"`
+	StoredAt time.Time       `json:"storedAt"`
+	Negative bool            `json:"negative"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// NewDiskCache creates a DiskCache in dir, creating the directory if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *DiskCache) Get(key string) (CacheItem, bool) {
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return CacheItem{}, false
+	}
+	var file diskCacheFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Key != key {
+		return CacheItem{}, false
+	}
+	return CacheItem{Value: file.Value, StoredAt: file.StoredAt, Negative: file.Negative}, true
+}
+
+func (c *DiskCache) Set(key string, item CacheItem) {
+	data, err := json.Marshal(diskCacheFile{Key: key, StoredAt: item.StoredAt, Negative: item.Negative, Value: item.Value})
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("key", key)).Warn("disk cache entry json marshal failed")
+		return
+	}
+
+	// write to a temporary file first so that readers never see a partial entry
+	tmp, err := ioutil.TempFile(c.dir, "tmp-*")
+	if err != nil {
+		zap.L().With(zap.Error(err), zap.String("key", key)).Warn("disk cache write failed")
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		zap.L().With(zap.Error(err), zap.String("key", key)).Warn("disk cache write failed")
+	}
+}
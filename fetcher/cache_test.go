@@ -0,0 +1,166 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Set("a", CacheItem{Value: []byte("1")})
+	c.Set("b", CacheItem{Value: []byte("2")})
+	c.Get("a")
+	c.Set("c", CacheItem{Value: []byte("3")})
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("least recently used entry b was not evicted")
+	}
+	if item, ok := c.Get("a"); !ok || string(item.Value) != "1" {
+		t.Errorf("Get(a) = %s, %v", item.Value, ok)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := time.Now().Add(-time.Minute).Round(time.Second)
+	c.Set("identity/Superrare/0x1", CacheItem{Value: []byte(`{"Msg":""}`), StoredAt: stored, Negative: true})
+
+	// a new instance on the same directory sees the entry
+	c, _ = NewDiskCache(dir)
+	item, ok := c.Get("identity/Superrare/0x1")
+	if !ok || string(item.Value) != `{"Msg":""}` || !item.StoredAt.Equal(stored) || !item.Negative {
+		t.Errorf("Get() = %+v, %v", item, ok)
+	}
+	if _, ok := c.Get("identity/Superrare/0x2"); ok {
+		t.Error("Get() found an entry that was never set")
+	}
+}
+
+func countingSuperrare(t *testing.T, body *atomic.Value) (*int32, []Option) {
+	srv := newTestServer(t)
+	var calls int32
+	srv.Handle("/api/v2/user", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(body.Load().(string)))
+	})
+	return &calls, []Option{
+		WithBaseURL(SUPERRARE, srv.URL),
+		onlySources(SUPERRARE),
+	}
+}
+
+func TestFetchIdentityCache(t *testing.T) {
+	var body atomic.Value
+	body.Store(`{"result": {"username": "brantly"}}`)
+	calls, opts := countingSuperrare(t, &body)
+	f := NewFetcher(append(opts, WithCache(NewLRUCache(100), CacheTTL{TTL: time.Hour}))...)
+
+	for i := 0; i < 3; i++ {
+		ids, err := f.FetchIdentity(testAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := statusOf(t, ids.Status, SUPERRARE)
+		if s.CacheHit != (i > 0) || s.Stale {
+			t.Errorf("call %d: status = %+v", i, s)
+		}
+		if len(ids.Superrare) != 1 || ids.Superrare[0].Username != "brantly" {
+			t.Errorf("call %d: Superrare = %+v", i, ids.Superrare)
+		}
+	}
+	if *calls != 1 {
+		t.Errorf("upstream called %d times, want 1", *calls)
+	}
+}
+
+func TestFetchIdentityNegativeCache(t *testing.T) {
+	var body atomic.Value
+	body.Store(`{"result": {}}`)
+	calls, opts := countingSuperrare(t, &body)
+
+	f := NewFetcher(append(opts, WithCache(NewLRUCache(100), CacheTTL{TTL: time.Hour}))...)
+	f.FetchIdentity(testAddress)
+	f.FetchIdentity(testAddress)
+	if *calls != 2 {
+		t.Errorf("empty result cached without NegativeTTL, upstream called %d times", *calls)
+	}
+
+	*calls = 0
+	f = NewFetcher(append(opts,
+		WithCache(NewLRUCache(100), CacheTTL{TTL: time.Hour}),
+		WithSourceCacheTTL(SUPERRARE, CacheTTL{TTL: time.Hour, NegativeTTL: time.Minute}),
+	)...)
+	f.FetchIdentity(testAddress)
+	ids, _ := f.FetchIdentity(testAddress)
+	if s := statusOf(t, ids.Status, SUPERRARE); *calls != 1 || !s.CacheHit || s.State != SourceEmpty {
+		t.Errorf("status = %+v after %d upstream calls, want a cached empty result", s, *calls)
+	}
+}
+
+func TestFetchIdentityStaleWhileRevalidate(t *testing.T) {
+	var body atomic.Value
+	body.Store(`{"result": {"username": "old"}}`)
+	calls, opts := countingSuperrare(t, &body)
+	f := NewFetcher(append(opts, WithCache(NewLRUCache(100), CacheTTL{TTL: 20 * time.Millisecond, StaleTTL: time.Hour}))...)
+
+	f.FetchIdentity(testAddress)
+	time.Sleep(30 * time.Millisecond)
+	body.Store(`{"result": {"username": "new"}}`)
+
+	ids, _ := f.FetchIdentity(testAddress)
+	if s := statusOf(t, ids.Status, SUPERRARE); !s.CacheHit || !s.Stale || ids.Superrare[0].Username != "old" {
+		t.Errorf("status = %+v, Superrare = %+v, want the stale entry", s, ids.Superrare)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		ids, _ = f.FetchIdentity(testAddress)
+		if ids.Superrare[0].Username == "new" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the stale entry was never refreshed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if s := statusOf(t, ids.Status, SUPERRARE); !s.CacheHit || s.Stale {
+		t.Errorf("status = %+v, want a fresh cache hit", s)
+	}
+	if n := atomic.LoadInt32(calls); n != 2 {
+		t.Errorf("upstream called %d times, want 2", n)
+	}
+}
+
+func TestFetchConnectionsCache(t *testing.T) {
+	srv := newTestServer(t)
+	f := newTestFetcher(t, srv, WithCache(NewLRUCache(100), CacheTTL{TTL: time.Hour}))
+
+	first, err := f.FetchConnections(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the upstream is gone, every source is served from the cache
+	srv.Close()
+	result, err := f.FetchConnectionsWithContext(context.Background(), testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Conn) != len(first) {
+		t.Errorf("got %d connections from the cache, want %d", len(result.Conn), len(first))
+	}
+	for _, s := range result.Status {
+		if !s.CacheHit || s.State != SourceSuccess {
+			t.Errorf("status = %+v, want a cache hit", s)
+		}
+	}
+}
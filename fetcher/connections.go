@@ -1,56 +1,150 @@
 package fetcher
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
-const ConnectionApiCount = 2
+// raribleFollowPageSize is the number of edges asked per page of Rarible followings and followers
+const raribleFollowPageSize = 1000
+
+type connectionResult struct {
+	source string
+	entry  ConnectionEntryList
+	status SourceStatus
+}
 
 func (f *fetcher) FetchConnections(address string) (results []ConnectionEntry, err error) {
-	ch := make(chan ConnectionEntryList)
+	result, err := f.FetchConnectionsWithContext(context.Background(), address)
+	return result.Conn, err
+}
+
+// FetchConnectionsWithContext fans out to every connection source with ctx bound to their requests
+// input is an Ethereum address or an ENS name, names are resolved first and reported in ConnectionResult.Name
+// if ctx is done before all sources have answered, the connections aggregated so far are returned with ctx.Err()
+// ConnectionResult.Status reports the outcome of every source, including those that never answered
+func (f *fetcher) FetchConnectionsWithContext(ctx context.Context, input string) (result ConnectionResult, err error) {
+	address, name, err := f.resolveInput(ctx, input)
+	result.Address, result.Name = address, name
+	if err != nil {
+		return result, err
+	}
 
-	// Part 1 - Demo data source
-	// Context API
-	go f.processContextConn(address, ch)
-	// Rarible API
-	go f.processRaribleConn(address, ch)
-	// Part 2 - Add other data source here
-	// TODO
+	sources := f.enabledConnectionSources()
+	// buffered so that late workers never block once we stop receiving
+	ch := make(chan connectionResult, len(sources))
+
+	start := time.Now()
+	pending := make(map[string]bool)
+	for _, src := range sources {
+		pending[src.Name()] = true
+		go func(src ConnectionSource) {
+			ch <- f.queryConnectionSource(ctx, src, address)
+		}(src)
+	}
 
 	// Final Part - Aggregate all data & convert ens domain & filter out invalid connections
-	for i := 0; i < ConnectionApiCount; i++ {
-		entry := <-ch
-		if entry.Err != nil {
-			zap.L().With(zap.Error(entry.Err)).Error("connection api error: " + entry.msg)
+	for i := 0; i < len(sources); i++ {
+		var res connectionResult
+		select {
+		case res = <-ch:
+		case <-ctx.Done():
+			zap.L().With(zap.Error(ctx.Err())).Warn("connection fetch stopped before all sources answered")
+			for _, src := range sources {
+				if pending[src.Name()] {
+					result.Status = append(result.Status, pendingSourceStatus(src.Name(), ctx, time.Since(start)))
+				}
+			}
+			return result, ctx.Err()
+		}
+		delete(pending, res.source)
+		result.Status = append(result.Status, res.status)
+		if res.entry.Err != nil {
+			zap.L().With(zap.Error(res.entry.Err)).Error("connection api error: " + res.entry.msg)
 			continue
 		}
-		results = append(results, entry.Conn...)
+		result.Conn = append(result.Conn, res.entry.Conn...)
 	}
 
-	return
+	return result, f.checkRequiredSources(result.Status)
 }
 
-func (f *fetcher) getRaribleConnection(address string, isFollowing bool) ([]RaribleConnectionResp, error) {
-	// Prepare request
-	var url string
-	if isFollowing {
-		url = fmt.Sprintf(RaribleFollowingUrl, address)
-	} else {
-		url = fmt.Sprintf(RaribleFollowerUrl, address)
+// queryConnectionSource serves src from the cache when possible, otherwise runs it behind its circuit breaker,
+// and reports how it went
+func (f *fetcher) queryConnectionSource(ctx context.Context, src ConnectionSource, address string) connectionResult {
+	stats := &requestStats{}
+	begin := time.Now()
+	key := cacheKey("connection", src.Name(), address)
+
+	var entry ConnectionEntryList
+	state := f.cacheGet(src.Name(), key, &entry)
+	switch state {
+	case cacheStale:
+		f.refreshInBackground(key, func(ctx context.Context) {
+			f.fetchConnectionEntry(ctx, src, address, key)
+		})
+	case cacheMiss:
+		entry = f.fetchConnectionEntry(withRequestStats(ctx, stats), src, address, key)
+	}
+
+	status := newSourceStatus(src.Name(), entry.Err, entry.msg, len(entry.Conn) == 0, time.Since(begin), stats)
+	status.CacheHit = state != cacheMiss
+	status.Stale = state == cacheStale
+	status.Truncated = entry.Truncated
+	return connectionResult{
+		source: src.Name(),
+		entry:  entry,
+		status: status,
+	}
+}
+
+// fetchConnectionEntry runs src behind its circuit breaker and caches successful results under key
+func (f *fetcher) fetchConnectionEntry(ctx context.Context, src ConnectionSource, address, key string) ConnectionEntryList {
+	b := f.breaker(src.Name())
+	if b != nil && !b.allow() {
+		return ConnectionEntryList{
+			Err: ErrSourceUnavailable,
+			msg: "[" + src.Name() + "] skipped, circuit breaker open",
+		}
 	}
 
-	postBody, _ := json.Marshal(map[string]int{
-		"size": 5000, // TODO
+	entry := src.FetchConnections(ctx, address)
+	if b != nil {
+		b.record(breakerOutcomeOf(ctx, entry.Err))
+	}
+	if entry.Err == nil {
+		f.cacheSet(src.Name(), key, entry, len(entry.Conn) == 0)
+	}
+	return entry
+}
+
+// getRaribleConnectionPage fetches up to size edges of url following continuation, "" for the first page
+func (f *fetcher) getRaribleConnectionPage(ctx context.Context, url, continuation string, size int) ([]RaribleConnectionResp, error) {
+	postBody, err := json.Marshal(RaribleConnectionReq{
+		Size:         size,
+		Continuation: continuation,
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	body, err := sendRequest(f.httpClient, RequestArgs{
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: RARIBLE,
 		url:    url,
 		method: "POST",
 		body:   postBody,
+		// the POST only carries the listing parameters
+		idempotent: true,
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	var results []RaribleConnectionResp
 	err = json.Unmarshal(body, &results)
@@ -60,27 +154,63 @@ func (f *fetcher) getRaribleConnection(address string, isFollowing bool) ([]Rari
 	return results, nil
 }
 
-func (f *fetcher) processRaribleConn(address string, ch chan<- ConnectionEntryList) {
+// getRaribleConnection pages through the followings or followers of address until exhaustion or maxEdges edges,
+// 0 for no cap, truncated is set when edges were left out
+func (f *fetcher) getRaribleConnection(ctx context.Context, address string, isFollowing bool, maxEdges int) (results []RaribleConnectionResp, truncated bool, err error) {
+	// Prepare request
+	var url string
+	if isFollowing {
+		url = fmt.Sprintf(RaribleFollowingUrl, address)
+	} else {
+		url = fmt.Sprintf(RaribleFollowerUrl, address)
+	}
+
+	continuation := ""
+	for {
+		size := raribleFollowPageSize
+		if maxEdges > 0 && maxEdges-len(results) < size {
+			// one edge past the cap tells whether edges are left out
+			size = maxEdges - len(results) + 1
+		}
+		page, err := f.getRaribleConnectionPage(ctx, url, continuation, size)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, edge := range page {
+			if maxEdges > 0 && len(results) >= maxEdges {
+				return results, true, nil
+			}
+			results = append(results, edge)
+		}
+		// Rarible may serve fewer edges than asked, only an empty page ends the listing
+		if len(page) == 0 || page[len(page)-1].ID == "" {
+			return results, false, nil
+		}
+		continuation = page[len(page)-1].ID
+	}
+}
+
+func (f *fetcher) processRaribleConn(ctx context.Context, address string) ConnectionEntryList {
 	var rarTotal []RaribleConnectionResp
 	result := ConnectionEntryList{}
 
 	// Query Followings from Rarible
-	rarFollowings, err := f.getRaribleConnection(address, true)
+	maxEdges := f.maxEdges(RARIBLE)
+	rarFollowings, truncated, err := f.getRaribleConnection(ctx, address, true, maxEdges)
 	if err != nil {
 		result.Err = err
 		result.msg = "[processRaribleConn] fetch Rarible followings failed"
-		ch <- result
-		return
+		return result
 	}
 
 	// Query Followers from Rarible
-	rarFollowers, err := f.getRaribleConnection(address, false)
+	rarFollowers, followersTruncated, err := f.getRaribleConnection(ctx, address, false, maxEdges)
 	if err != nil {
 		result.Err = err
 		result.msg = "[processRaribleConn] fetch Rarible followers failed"
-		ch <- result
-		return
+		return result
 	}
+	result.Truncated = truncated || followersTruncated
 
 	// Merge and printing out for Rarible followings
 	rarTotal = append(rarFollowers, rarFollowings...)
@@ -98,10 +228,10 @@ func (f *fetcher) processRaribleConn(address string, ch chan<- ConnectionEntryLi
 	}
 
 	result.Conn = append(result.Conn, results...)
-	ch <- result
+	return result
 }
 
-func (f *fetcher) getUserContextConnection(address string, isFollowing bool) (results []ConnectionEntry, err error) {
+func (f *fetcher) getUserContextConnection(ctx context.Context, address string, isFollowing bool) (results []ConnectionEntry, err error) {
 	var url string
 
 	if isFollowing {
@@ -110,7 +240,8 @@ func (f *fetcher) getUserContextConnection(address string, isFollowing bool) (re
 		url = fmt.Sprintf(ContextUrl, address+"/followers")
 	}
 
-	body, err := sendRequest(f.httpClient, RequestArgs{
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: CONTEXT,
 		url:    url,
 		method: "GET",
 	})
@@ -170,34 +301,111 @@ func (f *fetcher) getUserContextConnection(address string, isFollowing bool) (re
 	return results, nil
 }
 
-func (f *fetcher) processContextConn(address string, ch chan<- ConnectionEntryList) {
+func (f *fetcher) processContextConn(ctx context.Context, address string) ConnectionEntryList {
 	result := ConnectionEntryList{}
-	followingResults, err := f.getUserContextConnection(address, true)
+	followingResults, err := f.getUserContextConnection(ctx, address, true)
 	if err != nil {
 		result.Err = err
 		result.msg = "[processContextConn] fetch Context followings failed"
-		ch <- result
-		return
+		return result
 	}
 
-	followerResults, err := f.getUserContextConnection(address, false)
+	followerResults, err := f.getUserContextConnection(ctx, address, false)
 	if err != nil {
 		result.Err = err
 		result.msg = "[processContextConn] fetch Context followers failed"
-		ch <- result
-		return
+		return result
 	}
 
 	followingResults = append(followingResults, followerResults...)
 	result.Conn = append(result.Conn, followingResults...)
-	ch <- result
+	return result
 }
 
-// return false if input is neither Ethereum address nor ENS
+func (f *fetcher) getConvoConnection(ctx context.Context, address string, isFollowing bool) (results []ConnectionEntry, err error) {
+	var url string
+	if isFollowing {
+		url = ConvoUrl + "/following"
+	} else {
+		url = ConvoUrl + "/followers"
+	}
+
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: CONVO,
+		url:    url,
+		method: "GET",
+		params: map[string]string{"address": address, "apikey": f.convoAPIKey()},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var convoRecord ConvoConnection
+	err = json.Unmarshal(body, &convoRecord)
+	if err != nil {
+		return nil, err
+	}
+	if !convoRecord.Success {
+		return nil, errors.New("convo api reported failure")
+	}
+
+	for _, counterpart := range convoRecord.Data {
+		if !addressFilter(counterpart.Address) {
+			continue
+		}
+		newConvoRecord := ConnectionEntry{
+			From:     address,
+			To:       counterpart.Address,
+			Platform: CONVO,
+		}
+		if !isFollowing {
+			newConvoRecord.From, newConvoRecord.To = counterpart.Address, address
+		}
+		results = append(results, newConvoRecord)
+	}
+	return results, nil
+}
+
+func (f *fetcher) processConvoConn(ctx context.Context, address string) ConnectionEntryList {
+	result := ConnectionEntryList{}
+	followingResults, err := f.getConvoConnection(ctx, address, true)
+	if err != nil {
+		result.Err = err
+		result.msg = "[processConvoConn] fetch Convo followings failed"
+		return result
+	}
+
+	followerResults, err := f.getConvoConnection(ctx, address, false)
+	if err != nil {
+		result.Err = err
+		result.msg = "[processConvoConn] fetch Convo followers failed"
+		return result
+	}
+
+	result.Conn = append(followingResults, followerResults...)
+	return result
+}
+
+// nameTLDs are the top-level domains of the naming systems accepted as connection endpoints,
+// ENS and Unstoppable Domains
+var nameTLDs = map[string]bool{
+	"eth": true, "crypto": true, "nft": true, "x": true, "wallet": true, "bitcoin": true, "dao": true,
+	"888": true, "zil": true, "blockchain": true, "polygon": true, "unstoppable": true,
+}
+
+// isName reports whether addr is a name under one of nameTLDs
+func isName(addr string) bool {
+	i := strings.LastIndexByte(addr, '.')
+	return i > 0 && nameTLDs[strings.ToLower(addr[i+1:])]
+}
+
+// return false if input is neither Ethereum address nor ENS or Unstoppable Domains name nor Farcaster FID in the fid:N form
 func addressFilter(addr string) bool {
 	if isAddress(addr) {
 		return true
-	} else if len(addr) > 4 && addr[len(addr)-4:] == ".eth" {
+	} else if isName(addr) {
+		return true
+	} else if isFid(addr) {
 		return true
 	} else {
 		return false
@@ -0,0 +1,177 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ens "github.com/wealdtech/go-ens/v3"
+	"go.uber.org/zap"
+)
+
+// ensTextKeys are the standard text records pulled for a name
+var ensTextKeys = []string{"avatar", "url", "com.twitter", "com.github", "email", "description"}
+
+// ENSReader reads the on-chain ENS records of names and addresses, it backs the ENS identity source
+type ENSReader interface {
+	NameResolver
+	// ReverseResolve returns the name set in the reverse record of address, "" if none is set
+	ReverseResolve(ctx context.Context, address string) (string, error)
+	// Records returns the text records of name for keys, unset keys are left out, and its contenthash
+	// in text form such as /ipfs/..., "" if unset
+	Records(ctx context.Context, name string, keys []string) (texts map[string]string, contenthash string, err error)
+}
+
+// ENSExpiryReader is implemented by ENS readers that can tell when a name expires, the expiry of a subdomain
+// is the one of its .eth registration
+type ENSExpiryReader interface {
+	// Expiry returns the time the registration of name expires, the zero time for names that do not expire
+	Expiry(ctx context.Context, name string) (time.Time, error)
+}
+
+// WithEthProvider sets the DataSource reported by the ENS source, INFURA by default
+func WithEthProvider(provider string) Option {
+	return func(f *fetcher) {
+		f.ethProvider = provider
+	}
+}
+
+func (r *ensResolver) ReverseResolve(ctx context.Context, address string) (string, error) {
+	var name string
+	err := r.run(ctx, func(backend bind.ContractBackend) error {
+		registry, err := ens.NewRegistry(backend)
+		if err != nil {
+			return err
+		}
+		addr := common.HexToAddress(address)
+		resolverAddr, err := registry.ResolverAddress(fmt.Sprintf("%x.addr.reverse", addr.Bytes()))
+		if err != nil {
+			return err
+		}
+		if resolverAddr == (common.Address{}) {
+			// no reverse record
+			return nil
+		}
+		resolver, err := ens.NewReverseResolverAt(backend, resolverAddr)
+		if err != nil {
+			return err
+		}
+		name, err = resolver.Name(addr)
+		return err
+	})
+	return name, err
+}
+
+func (r *ensResolver) Records(ctx context.Context, name string, keys []string) (map[string]string, string, error) {
+	texts := make(map[string]string)
+	var contenthash string
+	err := r.run(ctx, func(backend bind.ContractBackend) error {
+		resolver, err := ens.NewResolver(backend, name)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			value, err := resolver.Text(key)
+			if err != nil {
+				return err
+			}
+			if value != "" {
+				texts[key] = value
+			}
+		}
+		hash, err := resolver.Contenthash()
+		if err != nil || len(hash) == 0 {
+			// resolvers predating EIP-1577 do not implement contenthash
+			return nil
+		}
+		contenthash, err = ens.ContenthashToString(hash)
+		return err
+	})
+	return texts, contenthash, err
+}
+
+func (r *ensResolver) Expiry(ctx context.Context, name string) (time.Time, error) {
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 || labels[len(labels)-1] != "eth" {
+		// only .eth names are rented
+		return time.Time{}, nil
+	}
+	var expiry time.Time
+	err := r.run(ctx, func(backend bind.ContractBackend) error {
+		registrar, err := ens.NewBaseRegistrar(backend, "eth")
+		if err != nil {
+			return err
+		}
+		expires, err := registrar.Expiry(strings.Join(labels[len(labels)-2:], "."))
+		if err != nil {
+			return err
+		}
+		if expires.Sign() > 0 {
+			expiry = time.Unix(expires.Int64(), 0).UTC()
+		}
+		return nil
+	})
+	return expiry, err
+}
+
+// processEns does the reverse resolution of address on-chain and pulls the records of the name found,
+// the name is only marked Verified when it resolves back to address
+func (f *fetcher) processEns(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	reader, ok := f.nameResolver.(ENSReader)
+	if !ok {
+		result.Err = ErrNoNameResolver
+		result.Msg = "[processEns] no ENS reader configured"
+		return result
+	}
+
+	name, err := reader.ReverseResolve(ctx, address)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processEns] reverse resolution failed"
+		return result
+	}
+	if name == "" {
+		return result
+	}
+
+	// a reverse record can be set to any name, it only counts when the name points back at the address
+	resolved, err := reader.Resolve(ctx, name)
+	verified := err == nil && strings.EqualFold(resolved, address)
+
+	texts, contenthash, err := reader.Records(ctx, name, ensTextKeys)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processEns] fetch records failed"
+		return result
+	}
+
+	var expires time.Time
+	if expiryReader, ok := reader.(ENSExpiryReader); ok {
+		if expires, err = expiryReader.Expiry(ctx, name); err != nil {
+			// the expiry is extra metadata, the profile is reported with Expires left unknown
+			zap.L().With(zap.Error(err), zap.String("name", name)).Warn("[processEns] fetch expiry failed")
+			expires = time.Time{}
+		}
+	}
+
+	result.Ens = &UserEnsIdentity{
+		Ens:         name,
+		Verified:    verified,
+		Avatar:      texts["avatar"],
+		Url:         texts["url"],
+		Twitter:     texts["com.twitter"],
+		Github:      texts["com.github"],
+		Email:       texts["email"],
+		Description: texts["description"],
+		Contenthash: contenthash,
+		Reverse:     true,
+		Expires:     expires,
+		DataSource:  f.ethProvider,
+	}
+	return result
+}
@@ -0,0 +1,422 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/baseregistrar"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+// stubENS stands in for the ENS contracts, reverse maps addresses to names, texts holds the records of names
+// and expires their expiry, expiryErr fails every expiry lookup
+type stubENS struct {
+	stubResolver
+	reverse   map[string]string
+	texts     map[string]map[string]string
+	expires   map[string]time.Time
+	expiryErr error
+}
+
+func (r stubENS) Expiry(ctx context.Context, name string) (time.Time, error) {
+	return r.expires[name], r.expiryErr
+}
+
+func (r stubENS) ReverseResolve(ctx context.Context, address string) (string, error) {
+	return r.reverse[address], nil
+}
+
+func (r stubENS) Records(ctx context.Context, name string, keys []string) (map[string]string, string, error) {
+	texts, ok := r.texts[name]
+	if !ok {
+		return nil, "", errors.New("unregistered name")
+	}
+	return texts, "/ipfs/QmTest", nil
+}
+
+func TestProcessEns(t *testing.T) {
+	const other = "0x0000000000000000000000000000000000000001"
+	reader := stubENS{
+		stubResolver: stubResolver{"brantly.eth": testAddress, "fake.eth": testAddress},
+		reverse:      map[string]string{testAddress: "brantly.eth", other: "fake.eth"},
+		texts: map[string]map[string]string{
+			"brantly.eth": {"com.twitter": "brantlymillegan", "url": "https://brantly.xyz"},
+			"fake.eth":    {},
+		},
+		expires: map[string]time.Time{"brantly.eth": time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	f := NewFetcher(WithNameResolver(reader), WithEthProvider("Alchemy"))
+
+	entry := f.processEns(context.Background(), testAddress)
+	if entry.Err != nil {
+		t.Fatal(entry.Err)
+	}
+	want := UserEnsIdentity{
+		Ens:         "brantly.eth",
+		Verified:    true,
+		Url:         "https://brantly.xyz",
+		Twitter:     "brantlymillegan",
+		Contenthash: "/ipfs/QmTest",
+		Reverse:     true,
+		Expires:     time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC),
+		DataSource:  "Alchemy",
+	}
+	if entry.Ens == nil || *entry.Ens != want {
+		t.Errorf("processEns = %+v, want %+v", entry.Ens, want)
+	}
+
+	// the reverse record of other names a name resolving elsewhere
+	entry = f.processEns(context.Background(), other)
+	if entry.Err != nil || entry.Ens == nil || entry.Ens.Verified {
+		t.Errorf("processEns(other) = %+v, want an unverified name", entry.Ens)
+	}
+
+	entry = f.processEns(context.Background(), "0x0000000000000000000000000000000000000002")
+	if entry.Err != nil || !entry.isEmpty() {
+		t.Errorf("processEns without reverse record = %+v, want an empty entry", entry)
+	}
+
+	// a failed expiry lookup keeps the profile with an unknown expiry
+	reader.expiryErr = errors.New("registrar call failed")
+	f = NewFetcher(WithNameResolver(reader), WithEthProvider("Alchemy"))
+	entry = f.processEns(context.Background(), testAddress)
+	want.Expires = time.Time{}
+	if entry.Err != nil || entry.Ens == nil || *entry.Ens != want {
+		t.Errorf("processEns with failed expiry = %+v, %v, want %+v", entry.Ens, entry.Err, want)
+	}
+}
+
+func TestEnsSourceRegistration(t *testing.T) {
+	hasEns := func(f *fetcher) bool {
+		for _, src := range f.enabledIdentitySources() {
+			if src.Name() == ENS {
+				return true
+			}
+		}
+		return false
+	}
+	if hasEns(NewFetcher(WithNameResolver(stubResolver{}))) {
+		t.Error("ENS source registered for a resolver that cannot read records")
+	}
+	f := NewFetcher(WithNameResolver(stubENS{}))
+	if !hasEns(f) {
+		t.Error("ENS source not registered for an ENSReader")
+	}
+	if f.ethProvider != INFURA {
+		t.Errorf("default provider = %q, want %q", f.ethProvider, INFURA)
+	}
+
+	// a source given under the ENS name replaces the built-in one rather than running next to it
+	custom := NewIdentitySource(ENS, func(ctx context.Context, address string) IdentityEntry { return IdentityEntry{} })
+	var names int
+	for _, src := range NewFetcher(WithNameResolver(stubENS{}), WithIdentitySource(custom)).enabledIdentitySources() {
+		if src.Name() == ENS {
+			names++
+		}
+	}
+	if names != 1 {
+		t.Errorf("%d identity sources named %s, want the custom one only", names, ENS)
+	}
+}
+
+func TestFetchIdentityEnsPrecedence(t *testing.T) {
+	srv := newTestServer(t)
+	reader := stubENS{
+		stubResolver: stubResolver{"brantly.eth": testAddress},
+		reverse:      map[string]string{testAddress: "brantly.eth"},
+		texts:        map[string]map[string]string{"brantly.eth": {}},
+	}
+	f := newTestFetcher(t, srv, WithNameResolver(reader))
+
+	ids, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids.Ens != "brantly.eth" || statusOf(t, ids.Status, ENS).State != SourceSuccess {
+		t.Errorf("Ens = %q, status %+v", ids.Ens, statusOf(t, ids.Status, ENS))
+	}
+	var verified bool
+	for _, profile := range ids.EnsProfile {
+		verified = verified || profile.Verified
+	}
+	if !verified {
+		t.Errorf("EnsProfile = %+v, want the on-chain entry", ids.EnsProfile)
+	}
+
+	// Context reports brantly.eth too, the on-chain entry is the one kept
+	wantNames := []UserName{
+		{Name: "brantly.eth", Registry: ENS, Primary: true, Reverse: true, DataSource: INFURA},
+		{Name: "brantly.nft", Registry: UNSTOPPABLE, Primary: true, Reverse: true, DataSource: UNSTOPPABLE},
+		{Name: "brantly.crypto", Registry: UNSTOPPABLE, DataSource: UNSTOPPABLE},
+	}
+	if fmt.Sprint(ids.Names) != fmt.Sprint(wantNames) {
+		t.Errorf("Names = %+v, want %+v", ids.Names, wantNames)
+	}
+}
+
+func TestFetchIdentityUnverifiedEns(t *testing.T) {
+	srv := newTestServer(t)
+	// the reverse record of the address names vitalik.eth, which resolves to another address
+	reader := stubENS{
+		stubResolver: stubResolver{"vitalik.eth": "0x0000000000000000000000000000000000000001"},
+		reverse:      map[string]string{testAddress: "vitalik.eth"},
+		texts:        map[string]map[string]string{"vitalik.eth": {}},
+	}
+	f := newTestFetcher(t, srv, WithNameResolver(reader))
+
+	ids, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids.Ens != "brantly.eth" || len(ids.EnsProfile) != 2 {
+		t.Errorf("Ens = %q, EnsProfile = %+v, want the Context name over the unverified reverse record", ids.Ens, ids.EnsProfile)
+	}
+}
+
+func TestMergeName(t *testing.T) {
+	expires := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	var names []UserName
+	for _, name := range []UserName{
+		{Name: "b.crypto", Registry: UNSTOPPABLE, DataSource: UNSTOPPABLE},
+		{Name: "brantly.eth", Registry: ENS, DataSource: CONTEXT},
+		{Name: "a.crypto", Registry: UNSTOPPABLE, DataSource: UNSTOPPABLE},
+		{Name: "Brantly.eth", Registry: ENS, Primary: true, Reverse: true, Expires: expires, DataSource: INFURA},
+	} {
+		names = mergeName(names, name)
+	}
+	want := []UserName{
+		{Name: "brantly.eth", Registry: ENS, Primary: true, Reverse: true, Expires: expires, DataSource: INFURA},
+		{Name: "a.crypto", Registry: UNSTOPPABLE, DataSource: UNSTOPPABLE},
+		{Name: "b.crypto", Registry: UNSTOPPABLE, DataSource: UNSTOPPABLE},
+	}
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("names = %+v, want %+v", names, want)
+	}
+}
+
+// ensChain stands in for an Ethereum JSON-RPC endpoint serving the ENS contracts, it answers eth_call for
+// the registry, one public resolver and the .eth registrar, names are keyed by namehash
+type ensChain struct {
+	owners    map[[32]byte]common.Address
+	resolvers map[[32]byte]common.Address
+	addrs     map[[32]byte]common.Address
+	names     map[[32]byte]string
+	texts     map[[32]byte]map[string]string
+	hashes    map[[32]byte][]byte
+	expires   map[string]int64
+}
+
+var (
+	ensRegistryAddr  = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+	ensResolverAddr  = common.HexToAddress("0x4976fb03C32e5B8cfe2b6cCB31c09Ba78EBaBa41")
+	ensRegistrarAddr = common.HexToAddress("0x57f1887a8BF19b14fC0dF6Fd9B2acc9Af147eA85")
+)
+
+func newENSChain() *ensChain {
+	return &ensChain{
+		owners:    make(map[[32]byte]common.Address),
+		resolvers: make(map[[32]byte]common.Address),
+		addrs:     make(map[[32]byte]common.Address),
+		names:     make(map[[32]byte]string),
+		texts:     make(map[[32]byte]map[string]string),
+		hashes:    make(map[[32]byte][]byte),
+		expires:   make(map[string]int64),
+	}
+}
+
+func mustNameHash(t *testing.T, name string) [32]byte {
+	t.Helper()
+	node, err := ens.NameHash(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node
+}
+
+// register gives name an owner and the public resolver, pointing it at addr
+func (c *ensChain) register(t *testing.T, name string, addr common.Address) [32]byte {
+	node := mustNameHash(t, name)
+	c.owners[node] = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	c.resolvers[node] = ensResolverAddr
+	c.addrs[node] = addr
+	return node
+}
+
+// setReverse points the reverse record of addr at name
+func (c *ensChain) setReverse(t *testing.T, addr common.Address, name string) {
+	node := c.register(t, fmt.Sprintf("%x.addr.reverse", addr.Bytes()), common.Address{})
+	c.names[node] = name
+}
+
+// serve starts the JSON-RPC endpoint, callers must Close it
+func (c *ensChain) serve(t *testing.T) *httptest.Server {
+	contracts := map[common.Address]string{
+		ensRegistryAddr:  registry.ContractABI,
+		ensResolverAddr:  resolver.ContractABI,
+		ensRegistrarAddr: baseregistrar.ContractABI,
+	}
+	parsed := make(map[common.Address]abi.ABI)
+	for addr, def := range contracts {
+		a, err := abi.JSON(strings.NewReader(def))
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed[addr] = a
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		reply := func(result interface{}) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+		}
+		switch req.Method {
+		case "eth_getCode":
+			reply("0x60")
+		case "eth_call":
+			var call struct {
+				To    common.Address `json:"to"`
+				Data  hexutil.Bytes  `json:"data"`
+				Input hexutil.Bytes  `json:"input"`
+			}
+			json.Unmarshal(req.Params[0], &call)
+			data := append(call.Data, call.Input...)
+			contract, ok := parsed[call.To]
+			if !ok || len(data) < 4 {
+				// no code at the address, an empty result as on chain
+				reply("0x")
+				return
+			}
+			method, err := contract.MethodById(data[:4])
+			if err != nil {
+				t.Errorf("eth_call to %s: %v", call.To.Hex(), err)
+				reply("0x")
+				return
+			}
+			args, err := method.Inputs.Unpack(data[4:])
+			if err != nil {
+				t.Errorf("eth_call %s: %v", method.Sig, err)
+			}
+			out, err := method.Outputs.Pack(c.call(method.Sig, args)...)
+			if err != nil {
+				t.Errorf("eth_call %s: %v", method.Sig, err)
+			}
+			reply(hexutil.Bytes(out))
+		default:
+			t.Errorf("unexpected JSON-RPC method %s", req.Method)
+		}
+	}))
+}
+
+// call answers the contract method sig called with args
+func (c *ensChain) call(sig string, args []interface{}) []interface{} {
+	switch sig {
+	case "owner(bytes32)":
+		return []interface{}{c.owners[args[0].([32]byte)]}
+	case "resolver(bytes32)":
+		return []interface{}{c.resolvers[args[0].([32]byte)]}
+	case "addr(bytes32)":
+		return []interface{}{c.addrs[args[0].([32]byte)]}
+	case "name(bytes32)":
+		return []interface{}{c.names[args[0].([32]byte)]}
+	case "text(bytes32,string)":
+		return []interface{}{c.texts[args[0].([32]byte)][args[1].(string)]}
+	case "contenthash(bytes32)":
+		hash := c.hashes[args[0].([32]byte)]
+		if hash == nil {
+			hash = []byte{}
+		}
+		return []interface{}{hash}
+	case "supportsInterface(bytes4)":
+		return []interface{}{true}
+	case "nameExpires(uint256)":
+		return []interface{}{big.NewInt(c.expires[args[0].(*big.Int).Text(16)])}
+	}
+	return nil
+}
+
+// setExpiry sets the expiry of the .eth name label, e.g. brantly
+func (c *ensChain) setExpiry(t *testing.T, label string, expires time.Time) {
+	hash, err := ens.LabelHash(label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.expires[new(big.Int).SetBytes(hash[:]).Text(16)] = expires.Unix()
+}
+
+// brantlyChain registers brantly.eth for testAddress with records and a reverse record, and a forged reverse
+// record of 0x...01 naming brantly.eth
+func brantlyChain(t *testing.T) *ensChain {
+	c := newENSChain()
+	c.owners[mustNameHash(t, "eth")] = ensRegistrarAddr
+	node := c.register(t, "brantly.eth", common.HexToAddress(testAddress))
+	c.texts[node] = map[string]string{"com.twitter": "brantlymillegan", "url": "https://brantly.xyz"}
+	hash, err := ens.StringToContenthash("/ipfs/QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.hashes[node] = hash
+	c.setReverse(t, common.HexToAddress(testAddress), "brantly.eth")
+	c.setReverse(t, common.HexToAddress("0x0000000000000000000000000000000000000001"), "brantly.eth")
+	c.register(t, "zero.eth", common.Address{})
+	c.setExpiry(t, "brantly", time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC))
+	return c
+}
+
+func TestProcessEnsContracts(t *testing.T) {
+	srv := brantlyChain(t).serve(t)
+	defer srv.Close()
+	client, err := ethclient.Dial(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := NewFetcher(WithEthBackend(client))
+
+	entry := f.processEns(context.Background(), testAddress)
+	if entry.Err != nil {
+		t.Fatal(entry.Err)
+	}
+	want := UserEnsIdentity{
+		Ens:      "brantly.eth",
+		Verified: true,
+		Url:      "https://brantly.xyz",
+		Twitter:  "brantlymillegan",
+		// go-ens reads contenthashes back as CIDv1
+		Contenthash: "/ipfs/k2jmtxseqz46solsx2rmxavgbzp6ij1t1kiq1or8a00c2g9bx1for0gv",
+		Reverse:     true,
+		Expires:     time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC),
+		DataSource:  INFURA,
+	}
+	if entry.Ens == nil || *entry.Ens != want {
+		t.Errorf("processEns = %+v, want %+v", entry.Ens, want)
+	}
+
+	// the reverse record names brantly.eth, which resolves to testAddress rather than back to 0x...01
+	entry = f.processEns(context.Background(), "0x0000000000000000000000000000000000000001")
+	if entry.Err != nil || entry.Ens == nil || entry.Ens.Ens != "brantly.eth" || entry.Ens.Verified {
+		t.Errorf("processEns(forged) = %+v, %v, want brantly.eth unverified", entry.Ens, entry.Err)
+	}
+
+	entry = f.processEns(context.Background(), "0x0000000000000000000000000000000000000002")
+	if entry.Err != nil || !entry.isEmpty() {
+		t.Errorf("processEns without reverse record = %+v, want an empty entry", entry)
+	}
+}
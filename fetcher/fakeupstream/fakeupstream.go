@@ -0,0 +1,189 @@
+// Package fakeupstream stands in for the third-party APIs queried by the fetcher.
+//
+// A Server replays JSON fixtures recorded in a directory, a Recorder wraps a live
+// http.RoundTripper and writes the fixtures the Server later serves. Both name a fixture after
+// the request method, path, query and a digest of the body, the upstream host is ignored so every
+// source can be pointed at the same Server.
+package fakeupstream
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// maxNameLen keeps fixture file names well below common file system limits
+const maxNameLen = 160
+
+// Fixture is the on-disk form of a recorded upstream response
+type Fixture struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9.\-]+`)
+
+// FixtureName returns the file name a request is recorded under and replayed from
+func FixtureName(method, path, rawQuery string, body []byte) string {
+	name := method + "_" + path
+	if rawQuery != "" {
+		name += "_" + rawQuery
+	}
+	name = strings.Trim(unsafeChars.ReplaceAllString(name, "_"), "_")
+	if len(name) > maxNameLen {
+		sum := sha256.Sum256([]byte(name))
+		name = name[:maxNameLen] + "_" + hex.EncodeToString(sum[:4])
+	}
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		name += "_" + hex.EncodeToString(sum[:4])
+	}
+	return name + ".json"
+}
+
+// Server serves the fixtures found in a directory, requests without a fixture get a 404
+type Server struct {
+	*httptest.Server
+
+	dir      string
+	mu       sync.Mutex
+	handlers map[string]http.HandlerFunc
+	missing  []string
+}
+
+// NewServer starts a Server replaying the fixtures in dir, callers must Close it
+func NewServer(dir string) *Server {
+	s := &Server{
+		dir:      dir,
+		handlers: make(map[string]http.HandlerFunc),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Handle makes every request to path be answered by h instead of a fixture,
+// tests use it to inject failures and slow responses
+func (s *Server) Handle(path string, h http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[path] = h
+}
+
+// Missing lists the fixture names requested but not found, in request order
+func (s *Server) Missing() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.missing...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	h, ok := s.handlers[r.URL.Path]
+	s.mu.Unlock()
+	if ok {
+		h(w, r)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	name := FixtureName(r.Method, r.URL.Path, r.URL.RawQuery, body)
+	fixture, err := ReadFixture(filepath.Join(s.dir, name))
+	if err != nil {
+		s.mu.Lock()
+		s.missing = append(s.missing, name)
+		s.mu.Unlock()
+		http.Error(w, fmt.Sprintf("no fixture %s", name), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(fixture.Status)
+	w.Write(fixture.Body)
+}
+
+// ReadFixture loads a fixture file
+func ReadFixture(path string) (Fixture, error) {
+	var fixture Fixture
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fixture, err
+	}
+	err = json.Unmarshal(data, &fixture)
+	if fixture.Status == 0 {
+		fixture.Status = http.StatusOK
+	}
+	return fixture, err
+}
+
+// Recorder is an http.RoundTripper writing every response it sees as a fixture
+type Recorder struct {
+	dir  string
+	next http.RoundTripper
+}
+
+// NewRecorder records the responses of next into dir, a nil next means http.DefaultTransport
+func NewRecorder(dir string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{dir: dir, next: next}
+}
+
+func (rec *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := rec.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	stored := respBody
+	if !json.Valid(stored) {
+		stored, _ = json.Marshal(string(respBody))
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, stored, "", "  "); err == nil {
+		stored = indented.Bytes()
+	}
+	data, err := json.MarshalIndent(Fixture{Status: resp.StatusCode, Body: stored}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(rec.dir, 0755); err != nil {
+		return nil, err
+	}
+	name := FixtureName(req.Method, req.URL.Path, req.URL.RawQuery, reqBody)
+	if err := ioutil.WriteFile(filepath.Join(rec.dir, name), append(data, '\n'), 0644); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
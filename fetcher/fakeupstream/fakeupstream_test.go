@@ -0,0 +1,91 @@
+package fakeupstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	live := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"path": "` + r.URL.Path + `"}`))
+	}))
+	defer live.Close()
+
+	dir := t.TempDir()
+	client := &http.Client{Transport: NewRecorder(dir, nil)}
+	for _, path := range []string{"/api/profile?address=0x1", "/missing"} {
+		resp, err := client.Get(live.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	resp, err := client.Post(live.URL+"/graphql", "application/json", strings.NewReader(`{"query": "{}"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	srv := NewServer(dir)
+	defer srv.Close()
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		want               string
+	}{
+		{"GET", "/api/profile?address=0x1", "", http.StatusOK, `{"path":"/api/profile"}`},
+		{"GET", "/missing", "", http.StatusNotFound, ""},
+		{"POST", "/graphql", `{"query": "{}"}`, http.StatusOK, `{"path":"/graphql"}`},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %s: status %d, want %d", tt.method, tt.path, resp.StatusCode, tt.status)
+		}
+		var compact bytes.Buffer
+		json.Compact(&compact, body)
+		if tt.want != "" && compact.String() != tt.want {
+			t.Errorf("%s %s: body %s, want %s", tt.method, tt.path, body, tt.want)
+		}
+	}
+	if missing := srv.Missing(); len(missing) != 0 {
+		t.Errorf("Missing() = %v", missing)
+	}
+
+	srv.Handle("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	resp, err = http.Post(srv.URL+"/graphql", "application/json", strings.NewReader(`{"query": "{}"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("overridden handler answered %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if missing := srv.Missing(); len(missing) != 1 || missing[0] != "GET_unknown.json" {
+		t.Errorf("Missing() = %v", missing)
+	}
+}
@@ -0,0 +1,231 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// farcasterPageSize is the number of links asked from the hub per page
+const farcasterPageSize = 100
+
+// fidPrefix marks a connection endpoint that is a Farcaster FID rather than an address, e.g. fid:3
+const fidPrefix = "fid:"
+
+func isFid(s string) bool {
+	if !strings.HasPrefix(s, fidPrefix) {
+		return false
+	}
+	_, err := strconv.ParseUint(s[len(fidPrefix):], 10, 64)
+	return err == nil
+}
+
+func fidEndpoint(fid uint64) string {
+	return fidPrefix + strconv.FormatUint(fid, 10)
+}
+
+// farcasterFid looks up the FID whose custody address is address, or failing that the FID that verified address,
+// found is false when there is none
+func (f *fetcher) farcasterFid(ctx context.Context, address string) (fid uint64, found bool, err error) {
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: FARCASTER,
+		url:    FarcasterHubUrl + "/onChainIdRegistryEventByAddress",
+		method: "GET",
+		params: map[string]string{"address": address},
+	})
+	if err != nil && !isHubNotFound(err) {
+		return 0, false, err
+	}
+	if err == nil {
+		var event FarcasterIdRegistryEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return 0, false, err
+		}
+		if event.Fid != 0 {
+			return event.Fid, true, nil
+		}
+	}
+
+	body, err = f.sendRequest(ctx, RequestArgs{
+		source: FARCASTER,
+		url:    FarcasterHubUrl + "/verificationsByAddress",
+		method: "GET",
+		params: map[string]string{"address": address},
+	})
+	if isHubNotFound(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	var verifications FarcasterVerifications
+	if err := json.Unmarshal(body, &verifications); err != nil {
+		return 0, false, err
+	}
+	for _, message := range verifications.Messages {
+		if message.Data.Fid != 0 {
+			return message.Data.Fid, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// isHubNotFound reports whether err is the not_found answer hubs give for an unknown address
+func isHubNotFound(err error) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound
+}
+
+func (f *fetcher) processFarcaster(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	fid, found, err := f.farcasterFid(ctx, address)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processFarcaster] fetch fid failed"
+		return result
+	}
+	if !found {
+		return result
+	}
+
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: FARCASTER,
+		url:    FarcasterHubUrl + "/userDataByFid",
+		method: "GET",
+		params: map[string]string{"fid": strconv.FormatUint(fid, 10)},
+	})
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processFarcaster] fetch user data failed"
+		return result
+	}
+
+	var userData FarcasterUserData
+	err = json.Unmarshal(body, &userData)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processFarcaster] user data response json unmarshal failed"
+		return result
+	}
+
+	newFarcasterRecord := UserFarcasterIdentity{
+		Fid:        fid,
+		DataSource: FARCASTER,
+	}
+	for _, message := range userData.Messages {
+		value := message.Data.UserDataBody.Value
+		switch message.Data.UserDataBody.Type {
+		case "USER_DATA_TYPE_USERNAME":
+			newFarcasterRecord.Username = value
+		case "USER_DATA_TYPE_DISPLAY":
+			newFarcasterRecord.DisplayName = value
+		case "USER_DATA_TYPE_BIO":
+			newFarcasterRecord.Bio = value
+		case "USER_DATA_TYPE_PFP":
+			newFarcasterRecord.Pfp = value
+		}
+	}
+	result.Farcaster = &newFarcasterRecord
+	return result
+}
+
+// getFarcasterLinks pages through the follow links of fid, the ones it makes or, for followers, the ones
+// targeting it, and returns the FIDs on the other end
+func (f *fetcher) getFarcasterLinks(ctx context.Context, fid uint64, isFollowing bool, maxEdges int) (fids []uint64, truncated bool, err error) {
+	url := FarcasterHubUrl + "/linksByFid"
+	fidParam := "fid"
+	if !isFollowing {
+		url = FarcasterHubUrl + "/linksByTargetFid"
+		fidParam = "target_fid"
+	}
+
+	pageToken := ""
+	for {
+		params := map[string]string{
+			fidParam:    strconv.FormatUint(fid, 10),
+			"link_type": "follow",
+			"pageSize":  strconv.Itoa(farcasterPageSize),
+		}
+		if pageToken != "" {
+			params["pageToken"] = pageToken
+		}
+		body, err := f.sendRequest(ctx, RequestArgs{
+			source: FARCASTER,
+			url:    url,
+			method: "GET",
+			params: params,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		var links FarcasterLinks
+		if err := json.Unmarshal(body, &links); err != nil {
+			return nil, false, err
+		}
+		for _, message := range links.Messages {
+			if maxEdges > 0 && len(fids) >= maxEdges {
+				return fids, true, nil
+			}
+			if isFollowing {
+				fids = append(fids, message.Data.LinkBody.TargetFid)
+			} else {
+				fids = append(fids, message.Data.Fid)
+			}
+		}
+		if links.NextPageToken == "" || len(links.Messages) == 0 {
+			return fids, false, nil
+		}
+		if maxEdges > 0 && len(fids) >= maxEdges {
+			// the hub only hands out a token while links are left
+			return fids, true, nil
+		}
+		pageToken = links.NextPageToken
+	}
+}
+
+// processFarcasterConn emits the follow links of the FID of address, the address stands on its own end
+// and the counterparts are given as fid:N
+func (f *fetcher) processFarcasterConn(ctx context.Context, address string) ConnectionEntryList {
+	result := ConnectionEntryList{}
+
+	fid, found, err := f.farcasterFid(ctx, address)
+	if err != nil {
+		result.Err = err
+		result.msg = "[processFarcasterConn] fetch fid failed"
+		return result
+	}
+	if !found {
+		return result
+	}
+
+	maxEdges := f.maxEdges(FARCASTER)
+	followings, truncated, err := f.getFarcasterLinks(ctx, fid, true, maxEdges)
+	if err != nil {
+		result.Err = err
+		result.msg = "[processFarcasterConn] fetch Farcaster followings failed"
+		return result
+	}
+	result.Truncated = truncated
+
+	followers, truncated, err := f.getFarcasterLinks(ctx, fid, false, maxEdges)
+	if err != nil {
+		result.Err = err
+		result.msg = "[processFarcasterConn] fetch Farcaster followers failed"
+		return result
+	}
+	result.Truncated = result.Truncated || truncated
+
+	for _, to := range followings {
+		result.Conn = append(result.Conn, ConnectionEntry{From: address, To: fidEndpoint(to), Platform: FARCASTER})
+	}
+	for _, from := range followers {
+		result.Conn = append(result.Conn, ConnectionEntry{From: fidEndpoint(from), To: address, Platform: FARCASTER})
+	}
+	return result
+}
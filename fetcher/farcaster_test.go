@@ -0,0 +1,138 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cyberconnecthq/indexer/fetcher/fakeupstream"
+)
+
+// farcasterVerifiedAddress has verified fid 1 on the stand-in hub without holding its custody
+const farcasterVerifiedAddress = "0x0000000000000000000000000000000000000002"
+
+// farcasterHub makes srv stand in for a hub under /hub where fid 1, held by testAddress and verified by
+// farcasterVerifiedAddress, follows n others and is followed by nobody, links are served two per page
+func farcasterHub(srv *fakeupstream.Server, n int) {
+	srv.Handle("/hub/v1/onChainIdRegistryEventByAddress", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("address") != testAddress {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errCode": "not_found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"fid": 1}`)
+	})
+	srv.Handle("/hub/v1/verificationsByAddress", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("address") != farcasterVerifiedAddress {
+			fmt.Fprint(w, `{"messages": []}`)
+			return
+		}
+		fmt.Fprintf(w, `{"messages": [{"data": {"fid": 1, "verificationAddEthAddressBody": {"address": "%s"}}}]}`, farcasterVerifiedAddress)
+	})
+	srv.Handle("/hub/v1/linksByFid", func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+		var messages []string
+		for i := offset; i < n && i < offset+2; i++ {
+			messages = append(messages, fmt.Sprintf(`{"data": {"fid": 1, "linkBody": {"type": "follow", "targetFid": %d}}}`, i+2))
+		}
+		next := ""
+		if offset+2 < n {
+			next = strconv.Itoa(offset + 2)
+		}
+		fmt.Fprintf(w, `{"messages": [%s], "nextPageToken": "%s"}`, strings.Join(messages, ","), next)
+	})
+	srv.Handle("/hub/v1/linksByTargetFid", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"messages": [], "nextPageToken": ""}`)
+	})
+}
+
+func TestFarcasterConnections(t *testing.T) {
+	tests := []struct {
+		links, maxEdges int
+		want            int
+		truncated       bool
+	}{
+		{5, 0, 5, false},
+		{5, 3, 3, true},
+		{5, 4, 4, true},
+		{4, 4, 4, false},
+	}
+	for _, tt := range tests {
+		srv := newTestServer(t)
+		farcasterHub(srv, tt.links)
+		opts := []Option{WithBaseURL(FARCASTER, srv.URL+"/hub")}
+		if tt.maxEdges != 0 {
+			opts = append(opts, WithMaxEdges(FARCASTER, tt.maxEdges))
+		}
+		f := NewFetcher(opts...)
+
+		entry := f.processFarcasterConn(context.Background(), testAddress)
+		if entry.Err != nil {
+			t.Fatal(entry.Err)
+		}
+		if len(entry.Conn) != tt.want || entry.Truncated != tt.truncated {
+			t.Errorf("%d links with cap %d: got %d edges, truncated %v, want %d, %v",
+				tt.links, tt.maxEdges, len(entry.Conn), entry.Truncated, tt.want, tt.truncated)
+		}
+		for i, c := range entry.Conn {
+			if c.From != testAddress || c.To != fidEndpoint(uint64(i+2)) || !addressFilter(c.To) {
+				t.Errorf("edge %d = %+v", i, c)
+			}
+		}
+	}
+}
+
+func TestFarcasterUnknownAddress(t *testing.T) {
+	srv := newTestServer(t)
+	farcasterHub(srv, 0)
+	f := NewFetcher(WithBaseURL(FARCASTER, srv.URL+"/hub"))
+
+	const other = "0x0000000000000000000000000000000000000001"
+	if entry := f.processFarcaster(context.Background(), other); entry.Err != nil || !entry.isEmpty() {
+		t.Errorf("identity = %+v, want empty for an address without FID", entry)
+	}
+	if entry := f.processFarcasterConn(context.Background(), other); entry.Err != nil || len(entry.Conn) != 0 {
+		t.Errorf("connections = %+v, want empty for an address without FID", entry)
+	}
+}
+
+func TestFarcasterVerifiedAddress(t *testing.T) {
+	srv := newTestServer(t)
+	farcasterHub(srv, 2)
+	srv.Handle("/hub/v1/userDataByFid", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"messages": [{"data": {"userDataBody": {"type": "USER_DATA_TYPE_USERNAME", "value": "one"}}}]}`)
+	})
+	f := NewFetcher(WithBaseURL(FARCASTER, srv.URL+"/hub"))
+
+	entry := f.processFarcaster(context.Background(), farcasterVerifiedAddress)
+	if entry.Err != nil {
+		t.Fatal(entry.Err)
+	}
+	if entry.Farcaster == nil || entry.Farcaster.Fid != 1 || entry.Farcaster.Username != "one" {
+		t.Errorf("identity = %+v, want fid 1 found through its verification", entry.Farcaster)
+	}
+	conn := f.processFarcasterConn(context.Background(), farcasterVerifiedAddress)
+	if conn.Err != nil || len(conn.Conn) != 2 || conn.Conn[0].From != farcasterVerifiedAddress {
+		t.Errorf("connections = %+v, want the 2 follows of fid 1", conn)
+	}
+}
+
+func TestFarcasterBadRequest(t *testing.T) {
+	srv := newTestServer(t)
+	farcasterHub(srv, 0)
+	srv.Handle("/hub/v1/onChainIdRegistryEventByAddress", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"errCode": "bad_request.validation_failure"}`)
+	})
+	f := NewFetcher(WithBaseURL(FARCASTER, srv.URL+"/hub"))
+
+	if entry := f.processFarcaster(context.Background(), testAddress); entry.Err == nil {
+		t.Errorf("identity = %+v, want the 400 reported as an error", entry)
+	}
+	if entry := f.processFarcasterConn(context.Background(), testAddress); entry.Err == nil {
+		t.Errorf("connections = %+v, want the 400 reported as an error", entry)
+	}
+}
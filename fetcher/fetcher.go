@@ -1,24 +1,83 @@
 package fetcher
 
 import (
+	"context"
 	"net/http"
+	"sync"
 )
 
+// Fetcher collects identity and connection data of an address, which may also be given as an ENS name
 type Fetcher interface {
 	// fetch following / follower data
 	FetchConnections(address string) ([]ConnectionEntry, error)
+	// fetch following / follower data, stopping at ctx cancellation or deadline
+	FetchConnectionsWithContext(ctx context.Context, address string) (ConnectionResult, error)
 	// fetch user identity data
 	FetchIdentity(address string) (IdentityEntryList, error)
+	// fetch user identity data, stopping at ctx cancellation or deadline
+	FetchIdentityWithContext(ctx context.Context, address string) (IdentityEntryList, error)
 }
 
 type fetcher struct {
-	httpClient *http.Client
+	httpClient    *http.Client
+	userAgent     string
+	sourceConfigs map[string]*sourceConfig
+	retryPolicy   RetryPolicy
+	limiters      map[string]*hostLimiter
+
+	breakerPolicy BreakerPolicy
+	breakerMu     sync.Mutex
+	breakers      map[string]*circuitBreaker
+
+	cache          Cache
+	cacheTTL       CacheTTL
+	sourceCacheTTL map[string]CacheTTL
+	refreshMu      sync.Mutex
+	refreshing     map[string]bool
+
+	sybil *sybilList
+	// tezosLinks are the Tezos accounts linked with WithTezosLink, keyed by lowercase Ethereum address
+	tezosLinks map[string][]string
+	// gitcoinScorer is the Gitcoin Passport scorer set with WithGitcoinScorer
+	gitcoinScorer string
+
+	nameResolver NameResolver
+	ethProvider  string
+	// twitterLookup enriches the discovered Twitter handles, nil disables the enrichment
+	twitterLookup TwitterLookup
+
+	sourceMu          sync.RWMutex
+	identitySources   []IdentitySource
+	connectionSources []ConnectionSource
+	disabledSources   map[string]bool
+	requiredSources   map[string]bool
 }
 
 var _ Fetcher = &fetcher{}
 
-func NewFetcher() *fetcher {
-	return &fetcher{
-		httpClient: httpClient(),
+// NewFetcher creates a fetcher with every built-in source registered, opts are applied in order
+func NewFetcher(opts ...Option) *fetcher {
+	f := &fetcher{
+		httpClient:      httpClient(),
+		sourceConfigs:   make(map[string]*sourceConfig),
+		retryPolicy:     DefaultRetryPolicy(),
+		limiters:        make(map[string]*hostLimiter),
+		breakerPolicy:   DefaultBreakerPolicy(),
+		breakers:        make(map[string]*circuitBreaker),
+		sourceCacheTTL:  make(map[string]CacheTTL),
+		refreshing:      make(map[string]bool),
+		disabledSources: make(map[string]bool),
+		requiredSources: make(map[string]bool),
+		sybil:           &sybilList{location: SybilUrl},
+		tezosLinks:      make(map[string][]string),
+		ethProvider:     INFURA,
+	}
+	f.registerDefaultSources()
+	for _, opt := range opts {
+		opt(f)
 	}
+	f.registerEthSources()
+	f.registerKeyedSources()
+	f.setupTwitterLookup()
+	return f
 }
@@ -0,0 +1,501 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cyberconnecthq/indexer/fetcher/fakeupstream"
+)
+
+// testAddress is brantly.eth, the fixtures in testdata/fixtures are recorded for it
+const testAddress = "0x983110309620d911731ac0932219af06091b6744"
+
+const fixtureDir = "testdata/fixtures"
+
+var record = flag.Bool("record", false, "refresh the fixtures in testdata/fixtures from the live upstream APIs")
+
+// testSources lists every built-in source that talks to an upstream API
+var testSources = []string{CONTEXT, SUPERRARE, FOUNDATION, FOUNDATION_SOCIAL, OPENSEA, ZORA, RARIBLE, SHOWTIME, SYBIL, CONVO, LENS, FARCASTER, POAP, MIRROR, SNAPSHOT, TEZOS, GITCOIN, UNSTOPPABLE}
+
+// onlySources disables every source of testSources but names
+func onlySources(names ...string) Option {
+	var disabled []string
+	for _, source := range testSources {
+		keep := false
+		for _, name := range names {
+			keep = keep || source == name
+		}
+		if !keep {
+			disabled = append(disabled, source)
+		}
+	}
+	return WithDisabledSources(disabled...)
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if *record {
+		if err := recordFixtures(); err != nil {
+			fmt.Fprintln(os.Stderr, "recording fixtures:", err)
+			os.Exit(1)
+		}
+	}
+	os.Exit(m.Run())
+}
+
+func recordFixtures() error {
+	f := NewFetcher(WithTransport(fakeupstream.NewRecorder(fixtureDir, nil)))
+	identity, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		return err
+	}
+	conn, err := f.FetchConnectionsWithContext(context.Background(), testAddress)
+	if err != nil {
+		return err
+	}
+	for _, s := range append(identity.Status, conn.Status...) {
+		if s.State == SourceError {
+			fmt.Fprintf(os.Stderr, "%s: %s: %v\n", s.Source, s.Msg, s.Err)
+		}
+	}
+	return nil
+}
+
+// testRetryPolicy keeps the default retry behavior without slowing the tests down
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:     3,
+	BaseDelay:       time.Millisecond,
+	MaxDelay:        10 * time.Millisecond,
+	RetryableStatus: DefaultRetryPolicy().RetryableStatus,
+}
+
+// newTestFetcher points every upstream source at srv
+func newTestFetcher(t *testing.T, srv *fakeupstream.Server, opts ...Option) *fetcher {
+	t.Helper()
+	// Unstoppable Domains is only registered with a key
+	all := []Option{WithRetryPolicy(testRetryPolicy), WithAPIKey(UNSTOPPABLE, "test-key")}
+	for _, source := range testSources {
+		all = append(all, WithBaseURL(source, srv.URL))
+	}
+	return NewFetcher(append(all, opts...)...)
+}
+
+func newTestServer(t *testing.T) *fakeupstream.Server {
+	t.Helper()
+	srv := fakeupstream.NewServer(fixtureDir)
+	t.Cleanup(func() {
+		srv.Close()
+		if missing := srv.Missing(); len(missing) > 0 {
+			t.Errorf("requests without fixture: %v", missing)
+		}
+	})
+	return srv
+}
+
+func statusOf(t *testing.T, statuses []SourceStatus, source string) SourceStatus {
+	t.Helper()
+	for _, s := range statuses {
+		if s.Source == source {
+			return s
+		}
+	}
+	t.Fatalf("no status for source %s in %+v", source, statuses)
+	return SourceStatus{}
+}
+
+func TestFetchIdentity(t *testing.T) {
+	srv := newTestServer(t)
+	f := newTestFetcher(t, srv, WithAPIKey(GITCOIN, "test-key"), WithGitcoinScorer("335"))
+
+	ids, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ids.Ens != "brantly.eth" {
+		t.Errorf("Ens = %q, want brantly.eth", ids.Ens)
+	}
+	if len(ids.Context) != 1 || ids.Context[0].FollowerCount != 1248 || ids.Context[0].Username != "brantly.eth" {
+		t.Errorf("Context = %+v", ids.Context)
+	}
+	if len(ids.Superrare) != 2 {
+		t.Fatalf("Superrare = %+v, want one entry from Context and one from Superrare", ids.Superrare)
+	}
+	for _, spr := range ids.Superrare {
+		if spr.DataSource == SUPERRARE && (spr.Username != "brantly" || spr.TwitterLink != "https://twitter.com/BrantlyMillegan") {
+			t.Errorf("Superrare = %+v", spr)
+		}
+	}
+	if len(ids.OpenSea) != 2 {
+		t.Fatalf("OpenSea = %+v, want one entry from Context and one from OpenSea", ids.OpenSea)
+	}
+	for _, sea := range ids.OpenSea {
+		if sea.DataSource == OPENSEA && (sea.Username != "brantly" || len(sea.Assets) != 2) {
+			t.Errorf("OpenSea = %+v", sea)
+		}
+	}
+	if len(ids.Zora) != 1 || len(ids.Zora[0].Collection) != 1 || len(ids.Zora[0].Creations) != 1 || ids.Zora[0].DataSource != ZORA {
+		t.Errorf("Zora = %+v", ids.Zora)
+	}
+	if len(ids.FoundationNonSocial) != 1 || len(ids.FoundationNonSocial[0].Nfts) != 1 || ids.FoundationNonSocial[0].NetRevenueInETH != "1.25" {
+		t.Errorf("FoundationNonSocial = %+v", ids.FoundationNonSocial)
+	}
+	if len(ids.Rarible) != 1 || ids.Rarible[0].Owned.Total != 2 || ids.Rarible[0].Created.Total != 1 || len(ids.Rarible[0].Activities) != 2 {
+		t.Errorf("Rarible = %+v", ids.Rarible)
+	}
+	wantFoundation := UserFoundationIdentity{
+		Username:        "brantly",
+		Bio:             "Director of Operations at ENS",
+		Twitter:         "BrantlyMillegan",
+		Website:         "brantly.xyz",
+		Instagram:       "brantly.eth",
+		VerifiedTwitter: "brantlymillegan",
+		DataSource:      FOUNDATION,
+	}
+	var foundFoundation bool
+	for _, fnd := range ids.Foundation {
+		if fnd.DataSource == FOUNDATION {
+			foundFoundation = true
+			if fnd != wantFoundation {
+				t.Errorf("Foundation = %+v, want %+v", fnd, wantFoundation)
+			}
+		}
+	}
+	if !foundFoundation {
+		t.Errorf("Foundation = %+v, want an entry from Foundation", ids.Foundation)
+	}
+	wantConvo := UserConvoIdentity{
+		DisplayName: "Brantly Millegan",
+		Bio:         "ENS",
+		Twitter:     "BrantlyMillegan",
+		Github:      "brantlymillegan",
+		Website:     "https://brantly.xyz",
+		DataSource:  CONVO,
+	}
+	if len(ids.Convo) != 1 || ids.Convo[0] != wantConvo {
+		t.Errorf("Convo = %+v", ids.Convo)
+	}
+	if len(ids.Lens) != 1 || ids.Lens[0].Handle != "brantly.lens" || ids.Lens[0].Avatar != "ipfs://QmAvatar" ||
+		ids.Lens[0].Attributes["twitter"] != "BrantlyMillegan" || ids.Lens[0].TotalFollowing != 3 {
+		t.Errorf("Lens = %+v", ids.Lens)
+	}
+	wantFarcaster := UserFarcasterIdentity{
+		Fid:         4211,
+		Username:    "brantly",
+		DisplayName: "Brantly Millegan",
+		Bio:         "ENS",
+		Pfp:         "https://i.imgur.com/brantly.png",
+		DataSource:  FARCASTER,
+	}
+	if len(ids.Farcaster) != 1 || ids.Farcaster[0] != wantFarcaster {
+		t.Errorf("Farcaster = %+v", ids.Farcaster)
+	}
+	if len(ids.Poap) != 2 || ids.Poap[0].EventID != 1587 || ids.Poap[0].Name != "ETHDenver 2021" ||
+		ids.Poap[0].Date != "19-Feb-2021" || ids.Poap[0].Chain != "xdai" || ids.Poap[1].ImageURL == "" {
+		t.Errorf("Poap = %+v", ids.Poap)
+	}
+	// the two revisions of the first publication are reported once, with the latest transaction
+	wantMirror := []UserMirrorPublication{
+		{
+			Title:       "Why ENS matters",
+			Digest:      "EsBn2rMFtMFkA8Wg9XQzLnkpBwJHVrdBhI0aMxeNvPs",
+			PublishedAt: time.Unix(1620000000, 0).UTC(),
+			ArweaveTx:   "x1Qe0ShFnl9WzB0uK9Y6K5pX1bV7mRk3vGhT2dJcN8A",
+			Contributor: testAddress,
+			DataSource:  MIRROR,
+		},
+		{
+			Title:       "Hello Mirror",
+			Digest:      "gT5mR2kW8xN1vB4cZ7yQ0jH3sL6dF9pA2eU5iO8nK1w",
+			PublishedAt: time.Unix(1617200000, 0).UTC(),
+			ArweaveTx:   "Tq7bN2xV5cM8zK1wR4yH6jD9gF3sA0eU7iP2oL5nB8m",
+			Contributor: testAddress,
+			DataSource:  MIRROR,
+		},
+	}
+	if fmt.Sprint(ids.Mirror) != fmt.Sprint(wantMirror) {
+		t.Errorf("Mirror = %+v, want %+v", ids.Mirror, wantMirror)
+	}
+	if len(ids.Snapshot) != 1 || len(ids.Snapshot[0].Spaces) != 2 || len(ids.Snapshot[0].Votes) != 2 {
+		t.Fatalf("Snapshot = %+v", ids.Snapshot)
+	}
+	wantVote := SnapshotVote{
+		Space:         "ens.eth",
+		ProposalID:    "QmYjQoVuhkp2KVbVYL8W3ViHmWsFHyqxXZ4Bd4ZNuKxX7k",
+		ProposalTitle: "Select service providers",
+		Choice:        "[1, 3]",
+		VotingPower:   34567.89,
+		VotedAt:       time.Unix(1635292800, 0).UTC(),
+	}
+	if vote := ids.Snapshot[0].Votes[1]; vote != wantVote {
+		t.Errorf("Snapshot vote = %+v, want %+v", vote, wantVote)
+	}
+	if space := ids.Snapshot[0].Spaces[0]; space.ID != "ens.eth" || space.Name != "ENS" || space.FollowedAt.IsZero() {
+		t.Errorf("Snapshot space = %+v", space)
+	}
+	wantShowtime := UserShowtimeIdentity{
+		Name:             "Brantly Millegan",
+		Username:         "brantly",
+		Bio:              "Director of Operations at ENS",
+		TwitterHandle:    "BrantlyMillegan",
+		LinkTreeHandle:   "brantly",
+		CryptoArtHandle:  "brantly",
+		FoundationHandle: "brantly",
+		HicetnuncHandle:  "tz1eY5Aqa1kXDFoiebL28emyXFoneAoVg1zh",
+		OpenseaHandle:    "brantly",
+		RaribleHandle:    "brantly",
+		DataSource:       SHOWTIME,
+	}
+	if len(ids.Showtime) != 1 || ids.Showtime[0] != wantShowtime {
+		t.Errorf("Showtime = %+v", ids.Showtime)
+	}
+
+	if len(ids.Tezos) != 1 || len(ids.Tezos[0].Objkts) != 2 {
+		t.Fatalf("Tezos = %+v", ids.Tezos)
+	}
+	if tz := ids.Tezos[0]; tz.Address != "tz1eY5Aqa1kXDFoiebL28emyXFoneAoVg1zh" || tz.LinkedVia != SHOWTIME || !tz.Verified ||
+		tz.Alias != "brantly" || tz.Balance != 12500000 || tz.Name != "Brantly" || tz.Twitter != "BrantlyMillegan" {
+		t.Errorf("Tezos = %+v", tz)
+	}
+	wantObjkt := TezosToken{Contract: HenObjktContract, TokenID: "36440", Name: "hicetnunc tribute", DisplayURI: "ipfs://QmObjkt36440", Balance: "3"}
+	if objkt := ids.Tezos[0].Objkts[1]; objkt != wantObjkt {
+		t.Errorf("Tezos objkt = %+v, want %+v", objkt, wantObjkt)
+	}
+
+	if len(ids.Gitcoin) != 1 || len(ids.Gitcoin[0].Contributions) != 2 || len(ids.Gitcoin[0].Stamps) != 3 {
+		t.Fatalf("Gitcoin = %+v", ids.Gitcoin)
+	}
+	if gc := ids.Gitcoin[0]; gc.Score != 32.415 || !gc.Passing || gc.Threshold != 20 || gc.ScoredAt.IsZero() || gc.DataSource != GITCOIN {
+		t.Errorf("Gitcoin = %+v", gc)
+	}
+	wantStamp := GitcoinStamp{Provider: "Discord", Score: 0.516, Expires: time.Date(2024, 12, 11, 17, 2, 9, 310000000, time.UTC)}
+	if stamp := ids.Gitcoin[0].Stamps[0]; stamp != wantStamp {
+		t.Errorf("Gitcoin stamp = %+v, want %+v", stamp, wantStamp)
+	}
+	if c := ids.Gitcoin[0].Contributions[1]; c.ChainID != 1 || c.AmountUSD != 25 || c.Amount != "25000000000000000000" ||
+		!c.DonatedAt.Equal(time.Date(2023, 4, 25, 9, 12, 3, 0, time.UTC)) {
+		t.Errorf("Gitcoin contribution = %+v", c)
+	}
+
+	if len(ids.Status) != len(testSources) {
+		t.Errorf("got %d statuses, want %d", len(ids.Status), len(testSources))
+	}
+	for _, s := range ids.Status {
+		if s.State != SourceSuccess || s.HTTPStatus != http.StatusOK {
+			t.Errorf("status = %+v, want success with HTTP 200", s)
+		}
+	}
+}
+
+func TestFetchConnections(t *testing.T) {
+	srv := newTestServer(t)
+	f := newTestFetcher(t, srv)
+
+	conn, err := f.FetchConnectionsWithContext(context.Background(), testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := make(map[string]int)
+	for _, c := range conn.Conn {
+		count[c.Platform]++
+		if c.From != testAddress && c.To != testAddress {
+			t.Errorf("connection %+v does not involve %s", c, testAddress)
+		}
+		if !addressFilter(c.From) || !addressFilter(c.To) {
+			t.Errorf("connection %+v has an invalid endpoint", c)
+		}
+	}
+	// the fixtures hold one entry per direction that has to be filtered out
+	if count[CONTEXT] != 3 || count[RARIBLE] != 3 || count[CONVO] != 3 || count[LENS] != 5 || count[FARCASTER] != 3 {
+		t.Errorf("connections per platform = %v, want 3 Context, 3 Rarible, 3 Convo, 5 Lens and 3 Farcaster", count)
+	}
+	for _, s := range conn.Status {
+		if s.State != SourceSuccess {
+			t.Errorf("status = %+v, want success", s)
+		}
+	}
+}
+
+func TestFetchIdentitySourceFailure(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Handle("/api/v2/user", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	f := newTestFetcher(t, srv)
+
+	ids, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spr := statusOf(t, ids.Status, SUPERRARE)
+	if spr.State != SourceError || spr.ErrKind != ErrKindHTTP || spr.HTTPStatus != http.StatusServiceUnavailable {
+		t.Errorf("Superrare status = %+v, want HTTP 503 error", spr)
+	}
+	var httpErr *HTTPError
+	if !errors.As(spr.Err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Superrare error = %v, want *HTTPError", spr.Err)
+	}
+	// the Superrare entry reported by Context is still merged
+	if len(ids.Superrare) != 1 || ids.Superrare[0].DataSource != CONTEXT {
+		t.Errorf("Superrare = %+v", ids.Superrare)
+	}
+
+	f = newTestFetcher(t, srv, WithRequiredSources(SUPERRARE))
+	ids, err = f.FetchIdentity(testAddress)
+	var reqErr *RequiredSourceError
+	if !errors.As(err, &reqErr) || len(reqErr.Failed) != 1 || reqErr.Failed[0].Source != SUPERRARE {
+		t.Errorf("err = %v, want *RequiredSourceError for Superrare", err)
+	}
+	if ids.Ens != "brantly.eth" {
+		t.Errorf("data of the other sources is missing: %+v", ids)
+	}
+}
+
+func TestFetchIdentityEmptySource(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Handle("/api/v2/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result": {}}`))
+	})
+	srv.Handle("/api/v2/profile_server/"+testAddress, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"profile": {"links": []}}}`))
+	})
+	srv.Handle("/v1/graphql", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"user": null}}`))
+	})
+	f := newTestFetcher(t, srv)
+
+	ids, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spr := statusOf(t, ids.Status, SUPERRARE); spr.State != SourceEmpty || spr.Err != nil {
+		t.Errorf("Superrare status = %+v, want empty", spr)
+	}
+	if s := statusOf(t, ids.Status, SHOWTIME); s.State != SourceEmpty || len(ids.Showtime) != 0 {
+		t.Errorf("Showtime status = %+v, Showtime = %+v, want empty", s, ids.Showtime)
+	}
+	if s := statusOf(t, ids.Status, FOUNDATION_SOCIAL); s.State != SourceEmpty {
+		t.Errorf("FoundationSocial status = %+v, want empty", s)
+	}
+}
+
+func TestFetchIdentityDeadline(t *testing.T) {
+	srv := newTestServer(t)
+	release := make(chan struct{})
+	defer close(release)
+	srv.Handle("/api/v1/account/"+testAddress, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	f := newTestFetcher(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	ids, err := f.FetchIdentityWithContext(ctx, testAddress)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("returned after %v, want promptly after the deadline", elapsed)
+	}
+	if sea := statusOf(t, ids.Status, OPENSEA); sea.State != SourceError || sea.ErrKind != ErrKindTimeout {
+		t.Errorf("OpenSea status = %+v, want timeout", sea)
+	}
+	if ids.Ens != "brantly.eth" {
+		t.Errorf("entries merged before the deadline are missing: %+v", ids)
+	}
+}
+
+func TestSourceRegistry(t *testing.T) {
+	srv := newTestServer(t)
+	custom := NewIdentitySource("Custom", func(ctx context.Context, address string) IdentityEntry {
+		return IdentityEntry{Custom: "custom:" + address}
+	})
+	f := newTestFetcher(t, srv,
+		WithIdentitySource(custom),
+		onlySources(CONTEXT),
+	)
+	if err := f.RegisterIdentitySource(custom); err == nil {
+		t.Error("registering a source twice succeeded")
+	}
+
+	ids, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids.Custom["Custom"] != "custom:"+testAddress {
+		t.Errorf("Custom = %v", ids.Custom)
+	}
+	if len(ids.Status) != 2 || len(ids.Zora) != 0 {
+		t.Errorf("disabled sources were queried: %+v", ids.Status)
+	}
+
+	f.EnableSource(ZORA)
+	ids, err = f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids.Status) != 3 {
+		t.Errorf("got %d statuses after enabling Zora, want 3", len(ids.Status))
+	}
+}
+
+func TestOptions(t *testing.T) {
+	var gotKey, gotAgent, gotHeader string
+	srv := newTestServer(t)
+	srv.Handle("/opensea/api/v1/account/"+testAddress, func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("X-API-KEY")
+		gotAgent = r.Header.Get("User-Agent")
+		gotHeader = r.Header.Get("X-Test")
+		w.Write([]byte(`{}`))
+	})
+	srv.Handle("/opensea/api/v1/assets", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	f := NewFetcher(
+		WithBaseURL(OPENSEA, srv.URL+"/opensea/"),
+		WithAPIKey(OPENSEA, "secret"),
+		WithHeader(OPENSEA, "X-Test", "yes"),
+		WithUserAgent("indexer-test"),
+		onlySources(OPENSEA),
+	)
+
+	ids, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := statusOf(t, ids.Status, OPENSEA); s.State != SourceEmpty {
+		t.Errorf("OpenSea status = %+v, want empty", s)
+	}
+	if gotKey != "secret" || gotAgent != "indexer-test" || gotHeader != "yes" {
+		t.Errorf("X-API-KEY = %q, User-Agent = %q, X-Test = %q", gotKey, gotAgent, gotHeader)
+	}
+}
+
+func TestConvoAPIKey(t *testing.T) {
+	var gotKey string
+	srv := newTestServer(t)
+	srv.Handle("/identity", func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.URL.Query().Get("apikey")
+		w.Write([]byte(`{"success": false}`))
+	})
+	f := newTestFetcher(t, srv, WithAPIKey(CONVO, "secret"))
+
+	entry := f.processConvo(context.Background(), testAddress)
+	if gotKey != "secret" {
+		t.Errorf("apikey = %q, want secret", gotKey)
+	}
+	if entry.Err == nil {
+		t.Error("a response without success was accepted")
+	}
+}
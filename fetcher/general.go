@@ -1,17 +1,37 @@
 package fetcher
 
+import (
+	"encoding/json"
+	"time"
+)
+
 const (
 	RARIBLE    = "Rarible"
 	CONTEXT    = "Context"
 	CONVO      = "Convo"
-	TWITTER    = "Twtter"
+	TWITTER    = "Twitter"
 	OPENSEA    = "Opensea"
 	ZORA       = "Zora"
 	FOUNDATION = "Foundation"
-	SHOWTIME   = "Showtime"
-	SYBIL      = "Sybil"
-	SUPERRARE  = "Superrare"
-	INFURA     = "Infura"
+	// FOUNDATION_SOCIAL is the source reading Foundation profiles, its entries have DataSource FOUNDATION
+	FOUNDATION_SOCIAL = "FoundationSocial"
+	LENS              = "Lens"
+	FARCASTER         = "Farcaster"
+	POAP              = "Poap"
+	MIRROR            = "Mirror"
+	SNAPSHOT          = "Snapshot"
+	TEZOS             = "Tezos"
+	UNSTOPPABLE       = "UnstoppableDomains"
+	GITCOIN           = "Gitcoin"
+	// SNAPSHOT_FOLLOWS is the connection source of space follows, its edges have platform SNAPSHOT
+	SNAPSHOT_FOLLOWS = "SnapshotFollows"
+	// POAP_COATTENDANCE is the platform of edges between holders of the same POAP event
+	POAP_COATTENDANCE = "PoapCoAttendance"
+	SHOWTIME          = "Showtime"
+	SYBIL             = "Sybil"
+	SUPERRARE         = "Superrare"
+	INFURA            = "Infura"
+	ENS               = "Ens"
 )
 
 const (
@@ -30,6 +50,9 @@ const (
 	// FoundationUrl Usage/Docs: https://thegraph.com/hosted-service/subgraph/f8n/fnd
 	FoundationUrl = "https://api.thegraph.com/subgraphs/name/f8n/fnd"
 
+	// FoundationSocialUrl is the GraphQL API behind foundation.app holding the user profiles
+	FoundationSocialUrl = "https://hasura2.foundation.app/v1/graphql"
+
 	// OpenSeaUrl Usage/Docs: https://docs.opensea.io/reference/api-overview
 	OpenSeaUrl = "https://api.opensea.io/api/v1"
 
@@ -41,13 +64,69 @@ const (
 
 	RaribleFollowingUrl = "https://api-mainnet.rarible.com/marketplace/api/v4/followings?owner=%s"
 	RaribleFollowerUrl  = "https://api-mainnet.rarible.com/marketplace/api/v4/followers?user=%s"
+
+	ShowtimeUrl = "https://showtime.io/api/v2/profile_server/%s"
+
+	// ConvoUrl Usage/Docs: https://docs.theconvo.space
+	ConvoUrl = "https://api.theconvo.space"
+	// ConvoPublicAPIKey is the shared key published in the Convo docs, set a key of your own with WithAPIKey(CONVO, key)
+	ConvoPublicAPIKey = "CONVO"
+
+	// LensUrl Usage/Docs: https://docs.lens.xyz/docs/introduction
+	LensUrl = "https://api.lens.dev/"
+
+	// FarcasterHubUrl is a public hub, Usage/Docs: https://docs.farcaster.xyz/reference/hubble/httpapi/httpapi
+	// any hub, or a stand-in serving the same HTTP API, can be used with WithBaseURL(FARCASTER, url)
+	FarcasterHubUrl = "https://nemes.farcaster.xyz:2281/v1"
+
+	// PoapUrl Usage/Docs: https://documentation.poap.tech/reference
+	PoapUrl = "https://api.poap.tech"
+
+	// ArweaveUrl is the Arweave gateway where Mirror stores its entries, Usage/Docs: https://gql-guide.vercel.app
+	ArweaveUrl = "https://arweave.net"
+
+	// SnapshotUrl Usage/Docs: https://docs.snapshot.org/graphql-api
+	SnapshotUrl = "https://hub.snapshot.org/graphql"
+
+	// TzktUrl Usage/Docs: https://api.tzkt.io
+	TzktUrl = "https://api.tzkt.io/v1"
+	// TzprofilesUrl is the GraphQL indexer of Tezos Profiles, Usage/Docs: https://tzprofiles.com
+	TzprofilesUrl = "https://indexer.tzprofiles.com/v1/graphql"
+	// HenObjktContract is the FA2 contract of hic et nunc OBJKTs
+	HenObjktContract = "KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton"
+
+	// GitcoinPassportUrl takes the scorer id and the address, Usage/Docs: https://docs.passport.xyz
+	GitcoinPassportUrl = "https://api.passport.xyz/v2/stamps/%s/score/%s"
+	// GitcoinGrantsUrl is the GraphQL indexer of Gitcoin Grants Stack, Usage/Docs: https://github.com/gitcoinco/grants-stack-indexer
+	GitcoinGrantsUrl = "https://grants-stack-indexer-v2.gitcoin.co/graphql"
+
+	// UnstoppableUrl Usage/Docs: https://docs.unstoppabledomains.com/openapi/resolution/
+	UnstoppableUrl = "https://api.unstoppabledomains.com/resolve"
+
+	// TwitterUrl Usage/Docs: https://developer.twitter.com/en/docs/twitter-api/users/lookup
+	TwitterUrl = "https://api.twitter.com/2/users/by/username/%s"
+
+	// SybilUrl Usage/Docs: https://github.com/Uniswap/sybil-list
+	SybilUrl = "https://raw.githubusercontent.com/Uniswap/sybil-list/master/verified.json"
 )
 
 type ConnectionEntryList struct {
 	Conn []ConnectionEntry
-	Err  error
-	msg  string
+	// Truncated is set when the source stopped paging at its WithMaxEdges cap
+	Truncated bool
+	Err       error
+	msg       string
+}
+
+// ConnectionResult is the aggregate of all connection sources for an address
+type ConnectionResult struct {
+	// Address is the address queried, Name the ENS name it was resolved from if a name was given
+	Address string
+	Name    string
+	Conn    []ConnectionEntry
+	Status  []SourceStatus
 }
+
 type ConnectionEntry struct {
 	From     string
 	To       string
@@ -55,6 +134,10 @@ type ConnectionEntry struct {
 }
 
 type IdentityEntryList struct {
+	// Address is the address queried, Name the ENS name it was resolved from if a name was given
+	Address string
+	Name    string
+
 	OpenSea             []UserOpenSeaIdentity
 	Twitter             []UserTwitterIdentity
 	Superrare           []UserSuperrareIdentity
@@ -64,7 +147,27 @@ type IdentityEntryList struct {
 	Foundation          []UserFoundationIdentity
 	FoundationNonSocial []UserFoundationIdentityNonSocial
 	Showtime            []UserShowtimeIdentity
-	Ens                 string
+	Convo               []UserConvoIdentity
+	Lens                []UserLensIdentity
+	Farcaster           []UserFarcasterIdentity
+	Poap                []UserPoapIdentity
+	Mirror              []UserMirrorPublication
+	Snapshot            []UserSnapshotIdentity
+	Tezos               []UserTezosIdentity
+	Gitcoin             []UserGitcoinIdentity
+	EnsProfile          []UserEnsIdentity
+	// Ens is the primary ENS name, a name verified on-chain takes precedence over one reported by Context,
+	// an unverified on-chain name is only kept in EnsProfile
+	Ens string
+	// Names holds every name of the address across naming systems, primary names first
+	Names []UserName
+	// TwitterProfiles are the Twitter accounts of the discovered handles, filled once the enrichment is configured
+	TwitterProfiles []UserTwitterProfile
+
+	// Custom holds the IdentityEntry.Custom value of every registered source that set one, keyed by source name
+	Custom map[string]interface{}
+	// Status reports the outcome of every queried source
+	Status []SourceStatus
 }
 
 type IdentityEntry struct {
@@ -78,15 +181,58 @@ type IdentityEntry struct {
 	Foundation          *UserFoundationIdentity
 	FoundationNonSocial *UserFoundationIdentityNonSocial
 	Showtime            *UserShowtimeIdentity
-	Err                 error
-	Msg                 string
+	Convo               *UserConvoIdentity
+	// Lens holds every profile owned by the address
+	Lens      []UserLensIdentity
+	Farcaster *UserFarcasterIdentity
+	// Poap holds every POAP badge of the address
+	Poap []UserPoapIdentity
+	// Mirror holds every publication authored by the address
+	Mirror   []UserMirrorPublication
+	Snapshot *UserSnapshotIdentity
+	// Tezos holds one entry per Tezos account linked to the address
+	Tezos   []UserTezosIdentity
+	Gitcoin *UserGitcoinIdentity
+	// Names are names owned by the address in naming systems other than ENS, ENS names are reported in Ens
+	Names []UserName
+	// Custom carries data of sources registered outside this package
+	Custom interface{}
+	Err    error
+	Msg    string
 }
 
 type UserTwitterIdentity struct {
-	Handle     string
+	Handle string
+	// VerifiedAt and TweetID are set for handles proven by a tweet, e.g. on the Sybil list
+	VerifiedAt time.Time
+	TweetID    string
 	DataSource string
 }
 
+// TwitterUser is the account a TwitterLookup finds for a handle
+type TwitterUser struct {
+	ID             string
+	Username       string
+	Name           string
+	Bio            string
+	FollowersCount int
+	FollowingCount int
+	TweetCount     int
+	// PinnedTweet is the text of the pinned tweet, "" if none
+	PinnedTweet string
+}
+
+type UserTwitterProfile struct {
+	TwitterUser
+	// MentionsAddress and MentionsName are set when the bio or the pinned tweet mention the address
+	// or one of its names
+	MentionsAddress bool
+	MentionsName    bool
+	// DiscoveredVia lists the sources the handle was found on
+	DiscoveredVia []string
+	DataSource    string
+}
+
 type UserRaribleIdentity struct {
 	Username string
 	Homepage string
@@ -144,7 +290,34 @@ type UserOpenSeaIdentity struct {
 }
 
 type UserEnsIdentity struct {
-	Ens        string
+	Ens string
+	// Verified is set when the forward resolution of Ens points back at the address
+	Verified bool
+	// Reverse is set when Ens was read from the reverse record of the address
+	Reverse bool
+	// Expires is when the .eth registration of Ens expires, zero when unknown
+	Expires     time.Time
+	Avatar      string
+	Url         string
+	Twitter     string
+	Github      string
+	Email       string
+	Description string
+	Contenthash string
+	DataSource  string
+}
+
+// UserName is a name of the address in a naming system such as ENS or Unstoppable Domains
+type UserName struct {
+	Name string
+	// Registry is the naming system of Name, ENS or UNSTOPPABLE
+	Registry string
+	// Primary is set for the name the address presents itself with, e.g. a verified ENS reverse record
+	Primary bool
+	// Reverse is set when the reverse record of the address points at Name
+	Reverse bool
+	// Expires is when the registration of Name expires, zero for names that do not expire or when unknown
+	Expires    time.Time
 	DataSource string
 }
 
@@ -170,18 +343,22 @@ type UserSuperrareIdentity struct {
 }
 
 type UserFoundationIdentity struct {
-	Username   string
-	Bio        string
-	Tiktok     string
-	Twitch     string
-	Discord    string
-	Twitter    string
-	Website    string
-	Youtube    string
-	Facebook   string
-	Snapchat   string
-	Instagram  string
-	DataSource string
+	Username  string
+	Bio       string
+	Tiktok    string
+	Twitch    string
+	Discord   string
+	Twitter   string
+	Website   string
+	Youtube   string
+	Facebook  string
+	Snapchat  string
+	Instagram string
+	// VerifiedTwitter and VerifiedInstagram are the usernames proven through Foundation's social verification,
+	// unlike Twitter and Instagram which are self-reported
+	VerifiedTwitter   string
+	VerifiedInstagram string
+	DataSource        string
 }
 
 type UserFoundationIdentityNonSocial struct {
@@ -242,7 +419,160 @@ type UserShowtimeIdentity struct {
 	DataSource       string
 }
 
+type UserConvoIdentity struct {
+	DisplayName string
+	Bio         string
+	Twitter     string
+	Github      string
+	Discord     string
+	Website     string
+	DataSource  string
+}
+
+type UserLensIdentity struct {
+	ProfileID      string
+	Handle         string
+	Name           string
+	Bio            string
+	Avatar         string
+	Attributes     map[string]string
+	TotalFollowers int
+	TotalFollowing int
+	DataSource     string
+}
+
+type UserFarcasterIdentity struct {
+	Fid         uint64
+	Username    string
+	DisplayName string
+	Bio         string
+	Pfp         string
+	DataSource  string
+}
+
+type UserPoapIdentity struct {
+	EventID int
+	TokenID string
+	Name    string
+	// Date is the start date of the event as given by POAP, e.g. 05-Nov-2021
+	Date       string
+	ImageURL   string
+	Chain      string
+	DataSource string
+}
+
+type UserMirrorPublication struct {
+	Title string
+	// Digest is the original content digest, it identifies the publication across its revisions
+	Digest      string
+	PublishedAt time.Time
+	// ArweaveTx is the transaction holding the latest revision
+	ArweaveTx string
+	// Contributor is the address that signed the entry
+	Contributor string
+	DataSource  string
+}
+
+type UserSnapshotIdentity struct {
+	Spaces     []SnapshotSpace
+	Votes      []SnapshotVote
+	DataSource string
+}
+
+// SnapshotSpace is a space followed by the address, spaces are identified by an ENS name
+type SnapshotSpace struct {
+	ID         string
+	Name       string
+	FollowedAt time.Time
+}
+
+type SnapshotVote struct {
+	Space         string
+	ProposalID    string
+	ProposalTitle string
+	// Choice is the JSON encoded choice, its form depends on the voting type of the proposal,
+	// e.g. 1 for single choice, [1,3] for approval and {"1":2,"2":1} for weighted voting
+	Choice      string
+	VotingPower float64
+	VotedAt     time.Time
+}
+
+type UserTezosIdentity struct {
+	Address string
+	// LinkedVia is where the link between the Ethereum address and Address comes from,
+	// SHOWTIME or TEZOS_LINK_EXPLICIT for links given with WithTezosLink
+	LinkedVia string
+	// Verified is set when the Tezos Profile of Address claims the Ethereum address back
+	Verified bool
+
+	// Alias, Balance in mutez and FirstActivity come from TzKT
+	Alias         string
+	Balance       int64
+	FirstActivity time.Time
+
+	// Name to Logo come from the Tezos Profile of Address
+	Name        string
+	Description string
+	Website     string
+	Twitter     string
+	Discord     string
+	Github      string
+	Logo        string
+
+	// Objkts are the hic et nunc OBJKTs held
+	Objkts     []TezosToken
+	DataSource string
+}
+
+type TezosToken struct {
+	Contract   string
+	TokenID    string
+	Name       string
+	DisplayURI string
+	Balance    string
+}
+
+type UserGitcoinIdentity struct {
+	// Score to Stamps come from Gitcoin Passport, they are left empty unless a scorer is configured
+	Score     float64
+	Passing   bool
+	Threshold float64
+	ScoredAt  time.Time
+	Expires   time.Time
+	Stamps    []GitcoinStamp
+
+	Contributions []GitcoinContribution
+	DataSource    string
+}
+
+type GitcoinStamp struct {
+	Provider string
+	Score    float64
+	Expires  time.Time
+}
+
+type GitcoinContribution struct {
+	ChainID   int
+	RoundID   string
+	ProjectID string
+	Recipient string
+	Token     string
+	// Amount is in the smallest unit of Token
+	Amount    string
+	AmountUSD float64
+	TxHash    string
+	DonatedAt time.Time
+}
+
+type RaribleConnectionReq struct {
+	Size int `json:"size"`
+	// Continuation is the ID of the last edge of the previous page
+	Continuation string `json:"continuation,omitempty"`
+}
+
 type RaribleConnectionResp struct {
+	// ID is the continuation of the page that ends with this edge
+	ID        string `json:"id"`
 	Following struct {
 		From string `json:"owner"`
 		To   string `json:"user"`
@@ -419,6 +749,317 @@ type RaribleUserActivityProfile struct {
 	} `json:"activities"`
 }
 
+type ShowtimeProfile struct {
+	Data struct {
+		Profile struct {
+			Name     string `json:"name"`
+			Username string `json:"username"`
+			Bio      string `json:"bio"`
+			Links    []struct {
+				TypeName   string `json:"type__name"`
+				TypePrefix string `json:"type__prefix"`
+				UserInput  string `json:"user_input"`
+			} `json:"links"`
+		} `json:"profile"`
+	} `json:"data"`
+}
+
+type ConvoIdentity struct {
+	Success bool `json:"success"`
+	Data    struct {
+		DisplayName string `json:"displayName"`
+		Bio         string `json:"bio"`
+		Links       struct {
+			Twitter string `json:"twitter"`
+			Github  string `json:"github"`
+			Discord string `json:"discord"`
+			Website string `json:"website"`
+		} `json:"links"`
+	} `json:"data"`
+}
+
+type ConvoConnection struct {
+	Success bool `json:"success"`
+	Data    []struct {
+		Address string `json:"address"`
+	} `json:"data"`
+}
+
+// GraphQLResp is the envelope of a GraphQL response, errors come with a 200 response
+type GraphQLResp struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type LensProfiles struct {
+	Profiles struct {
+		Items []struct {
+			ID         string `json:"id"`
+			Handle     string `json:"handle"`
+			Name       string `json:"name"`
+			Bio        string `json:"bio"`
+			Attributes []struct {
+				Key   string `json:"key"`
+				Value string `json:"value"`
+			} `json:"attributes"`
+			Picture struct {
+				// Original is set for a MediaSet picture, URI for an NftImage one
+				Original struct {
+					URL string `json:"url"`
+				} `json:"original"`
+				URI string `json:"uri"`
+			} `json:"picture"`
+			Stats struct {
+				TotalFollowers int `json:"totalFollowers"`
+				TotalFollowing int `json:"totalFollowing"`
+			} `json:"stats"`
+		} `json:"items"`
+		PageInfo LensPageInfo `json:"pageInfo"`
+	} `json:"profiles"`
+}
+
+// LensEdges is a page of either the following or the followers query
+type LensEdges struct {
+	Items []struct {
+		// Profile is set by the following query, Wallet by the followers query
+		Profile struct {
+			OwnedBy string `json:"ownedBy"`
+		} `json:"profile"`
+		Wallet struct {
+			Address string `json:"address"`
+		} `json:"wallet"`
+	} `json:"items"`
+	PageInfo LensPageInfo `json:"pageInfo"`
+}
+
+type LensPageInfo struct {
+	Next string `json:"next"`
+}
+
+type FarcasterIdRegistryEvent struct {
+	Fid uint64 `json:"fid"`
+}
+
+type FarcasterVerifications struct {
+	Messages []struct {
+		Data struct {
+			Fid uint64 `json:"fid"`
+		} `json:"data"`
+	} `json:"messages"`
+}
+
+type FarcasterUserData struct {
+	Messages []struct {
+		Data struct {
+			UserDataBody struct {
+				Type  string `json:"type"`
+				Value string `json:"value"`
+			} `json:"userDataBody"`
+		} `json:"data"`
+	} `json:"messages"`
+}
+
+type FarcasterLinks struct {
+	Messages []struct {
+		Data struct {
+			Fid      uint64 `json:"fid"`
+			LinkBody struct {
+				Type      string `json:"type"`
+				TargetFid uint64 `json:"targetFid"`
+			} `json:"linkBody"`
+		} `json:"data"`
+	} `json:"messages"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+type PoapEvent struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	StartDate string `json:"start_date"`
+	ImageURL  string `json:"image_url"`
+}
+
+type PoapToken struct {
+	Event   PoapEvent `json:"event"`
+	TokenID string    `json:"tokenId"`
+	Owner   string    `json:"owner"`
+	Chain   string    `json:"chain"`
+}
+
+type PoapEventHolders struct {
+	Total  int `json:"total"`
+	Tokens []struct {
+		Owner struct {
+			ID string `json:"id"`
+		} `json:"owner"`
+	} `json:"tokens"`
+}
+
+type ArweaveTransactions struct {
+	Transactions struct {
+		PageInfo struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Edges []struct {
+			Cursor string `json:"cursor"`
+			Node   struct {
+				ID    string `json:"id"`
+				Block struct {
+					Timestamp int64 `json:"timestamp"`
+				} `json:"block"`
+				Tags []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"tags"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"transactions"`
+}
+
+type MirrorEntry struct {
+	Content struct {
+		Title     string `json:"title"`
+		Timestamp int64  `json:"timestamp"`
+	} `json:"content"`
+	Digest     string `json:"digest"`
+	Authorship struct {
+		Contributor string `json:"contributor"`
+	} `json:"authorship"`
+}
+
+type SnapshotFollows struct {
+	Follows []struct {
+		ID    string `json:"id"`
+		Space struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"space"`
+		Created int64 `json:"created"`
+	} `json:"follows"`
+}
+
+type SnapshotVotes struct {
+	Votes []struct {
+		ID    string `json:"id"`
+		Space struct {
+			ID string `json:"id"`
+		} `json:"space"`
+		Proposal struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+		} `json:"proposal"`
+		Choice  json.RawMessage `json:"choice"`
+		Vp      float64         `json:"vp"`
+		Created int64           `json:"created"`
+	} `json:"votes"`
+}
+
+type TzktAccount struct {
+	Address           string    `json:"address"`
+	Alias             string    `json:"alias"`
+	Balance           int64     `json:"balance"`
+	FirstActivityTime time.Time `json:"firstActivityTime"`
+}
+
+type TzktTokenBalance struct {
+	Token struct {
+		Contract struct {
+			Address string `json:"address"`
+		} `json:"contract"`
+		TokenID  string `json:"tokenId"`
+		Metadata struct {
+			Name       string `json:"name"`
+			DisplayURI string `json:"displayUri"`
+		} `json:"metadata"`
+	} `json:"token"`
+	Balance string `json:"balance"`
+}
+
+type Tzprofile struct {
+	Profile *struct {
+		Alias       string `json:"alias"`
+		Description string `json:"description"`
+		Website     string `json:"website"`
+		Twitter     string `json:"twitter"`
+		Discord     string `json:"discord"`
+		Github      string `json:"github"`
+		Logo        string `json:"logo"`
+		Ethereum    string `json:"ethereum"`
+	} `json:"tzprofiles_by_pk"`
+}
+
+type GitcoinPassportScore struct {
+	Score              string     `json:"score"`
+	PassingScore       bool       `json:"passing_score"`
+	Threshold          string     `json:"threshold"`
+	LastScoreTimestamp *time.Time `json:"last_score_timestamp"`
+	ExpirationTime     *time.Time `json:"expiration_timestamp"`
+	Error              string     `json:"error"`
+	Stamps             map[string]struct {
+		Score          string     `json:"score"`
+		ExpirationDate *time.Time `json:"expiration_date"`
+	} `json:"stamps"`
+}
+
+type GitcoinDonations struct {
+	Donations []struct {
+		ChainID          int     `json:"chainId"`
+		RoundID          string  `json:"roundId"`
+		ProjectID        string  `json:"projectId"`
+		RecipientAddress string  `json:"recipientAddress"`
+		TokenAddress     string  `json:"tokenAddress"`
+		Amount           string  `json:"amount"`
+		AmountInUsd      float64 `json:"amountInUsd"`
+		Timestamp        string  `json:"timestamp"`
+		TransactionHash  string  `json:"transactionHash"`
+	} `json:"donations"`
+}
+
+type UnstoppableDomains struct {
+	Data []struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Meta struct {
+				Domain     string `json:"domain"`
+				Owner      string `json:"owner"`
+				Blockchain string `json:"blockchain"`
+				Reverse    bool   `json:"reverse"`
+			} `json:"meta"`
+		} `json:"attributes"`
+	} `json:"data"`
+	Meta struct {
+		NextStartingAfter string `json:"nextStartingAfter"`
+		HasMore           bool   `json:"hasMore"`
+	} `json:"meta"`
+}
+
+type TwitterUserResp struct {
+	Data *struct {
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		Username      string `json:"username"`
+		Description   string `json:"description"`
+		PinnedTweetID string `json:"pinned_tweet_id"`
+		PublicMetrics struct {
+			FollowersCount int `json:"followers_count"`
+			FollowingCount int `json:"following_count"`
+			TweetCount     int `json:"tweet_count"`
+		} `json:"public_metrics"`
+	} `json:"data"`
+	Includes struct {
+		Tweets []struct {
+			ID   string `json:"id"`
+			Text string `json:"text"`
+		} `json:"tweets"`
+	} `json:"includes"`
+	Errors []struct {
+		Type   string `json:"type"`
+		Detail string `json:"detail"`
+	} `json:"errors"`
+}
+
 type FoundationIdentity struct {
 	Data struct {
 		User struct {
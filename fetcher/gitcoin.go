@@ -0,0 +1,161 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gitcoinPageSize is the number of donations asked per page
+const gitcoinPageSize = 1000
+
+const gitcoinDonationsQuery = `query Donations($donor: String!, $first: Int!, $offset: Int!) {
+	donations(filter: {donorAddress: {equalTo: $donor}}, first: $first, offset: $offset, orderBy: TIMESTAMP_DESC) {
+		chainId roundId projectId recipientAddress tokenAddress amount amountInUsd timestamp transactionHash
+	}
+}`
+
+// WithGitcoinScorer sets the Gitcoin Passport scorer the GITCOIN source scores addresses with,
+// Passport is only queried once both the scorer and the key set with WithAPIKey(GITCOIN, key) are configured
+func WithGitcoinScorer(scorerID string) Option {
+	return func(f *fetcher) {
+		f.gitcoinScorer = scorerID
+	}
+}
+
+// parseGitcoinTime reads the timestamps of the grants indexer, which leaves out the zone of UTC times
+func parseGitcoinTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02T15:04:05", s)
+}
+
+func (f *fetcher) getGitcoinContributions(ctx context.Context, address string) ([]GitcoinContribution, error) {
+	// donors are indexed on the lowercase address
+	donor := strings.ToLower(address)
+	var contributions []GitcoinContribution
+	for offset := 0; ; offset += gitcoinPageSize {
+		var page GitcoinDonations
+		err := f.graphQLQuery(ctx, GITCOIN, GitcoinGrantsUrl, gitcoinDonationsQuery,
+			map[string]interface{}{"donor": donor, "first": gitcoinPageSize, "offset": offset}, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, donation := range page.Donations {
+			donatedAt, err := parseGitcoinTime(donation.Timestamp)
+			if err != nil {
+				return nil, err
+			}
+			contributions = append(contributions, GitcoinContribution{
+				ChainID:   donation.ChainID,
+				RoundID:   donation.RoundID,
+				ProjectID: donation.ProjectID,
+				Recipient: donation.RecipientAddress,
+				Token:     donation.TokenAddress,
+				Amount:    donation.Amount,
+				AmountUSD: donation.AmountInUsd,
+				TxHash:    donation.TransactionHash,
+				DonatedAt: donatedAt.UTC(),
+			})
+		}
+		if len(page.Donations) < gitcoinPageSize {
+			return contributions, nil
+		}
+	}
+}
+
+// getGitcoinPassport scores address with the configured scorer and fills in its score and stamps
+func (f *fetcher) getGitcoinPassport(ctx context.Context, address string, identity *UserGitcoinIdentity) error {
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: GITCOIN,
+		url:    fmt.Sprintf(GitcoinPassportUrl, f.gitcoinScorer, address),
+		method: "GET",
+		header: map[string]string{"X-API-KEY": f.apiKey(GITCOIN)},
+	})
+	if err != nil {
+		return err
+	}
+
+	var passport GitcoinPassportScore
+	if err := json.Unmarshal(body, &passport); err != nil {
+		return err
+	}
+	if passport.Error != "" {
+		return fmt.Errorf("gitcoin passport: %s", passport.Error)
+	}
+
+	if identity.Score, err = parseGitcoinScore(passport.Score); err != nil {
+		return err
+	}
+	if identity.Threshold, err = parseGitcoinScore(passport.Threshold); err != nil {
+		return err
+	}
+	identity.Passing = passport.PassingScore
+	if passport.LastScoreTimestamp != nil {
+		identity.ScoredAt = passport.LastScoreTimestamp.UTC()
+	}
+	if passport.ExpirationTime != nil {
+		identity.Expires = passport.ExpirationTime.UTC()
+	}
+	for provider, stamp := range passport.Stamps {
+		score, err := parseGitcoinScore(stamp.Score)
+		if err != nil {
+			return err
+		}
+		newStamp := GitcoinStamp{Provider: provider, Score: score}
+		if stamp.ExpirationDate != nil {
+			newStamp.Expires = stamp.ExpirationDate.UTC()
+		}
+		identity.Stamps = append(identity.Stamps, newStamp)
+	}
+	// stamps come as a map, keep their order stable
+	sort.Slice(identity.Stamps, func(i, j int) bool {
+		return identity.Stamps[i].Provider < identity.Stamps[j].Provider
+	})
+	return nil
+}
+
+// parseGitcoinScore reads the decimal strings Passport reports scores in, an empty score is 0
+func parseGitcoinScore(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// processGitcoin reports the grants contributions of address and, once a scorer is configured, its Passport score and stamps
+func (f *fetcher) processGitcoin(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	contributions, err := f.getGitcoinContributions(ctx, address)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processGitcoin] fetch contributions failed"
+		return result
+	}
+	identity := UserGitcoinIdentity{
+		Contributions: contributions,
+		DataSource:    GITCOIN,
+	}
+
+	if f.gitcoinScorer != "" && f.apiKey(GITCOIN) != "" {
+		// the contributions are kept, the error marks the result as partial so it is not cached
+		scored := identity
+		if err := f.getGitcoinPassport(ctx, address, &scored); err != nil {
+			result.Err = err
+			result.Msg = "[processGitcoin] fetch passport score failed"
+		} else {
+			identity = scored
+		}
+	}
+
+	if len(identity.Contributions) != 0 || len(identity.Stamps) != 0 || identity.Score != 0 {
+		result.Gitcoin = &identity
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGitcoinWithoutScorer(t *testing.T) {
+	srv := newTestServer(t)
+	// Passport is not queried without a scorer, a request would miss its fixture
+	f := newTestFetcher(t, srv, WithAPIKey(GITCOIN, "test-key"))
+
+	entry := f.processGitcoin(context.Background(), testAddress)
+	if entry.Err != nil {
+		t.Fatal(entry.Err)
+	}
+	if entry.Gitcoin == nil || len(entry.Gitcoin.Contributions) != 2 || entry.Gitcoin.Score != 0 || len(entry.Gitcoin.Stamps) != 0 {
+		t.Errorf("Gitcoin = %+v", entry.Gitcoin)
+	}
+}
+
+func TestGitcoinPassportError(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Handle("/v2/stamps/335/score/"+testAddress, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-KEY") != "test-key" {
+			t.Errorf("X-API-KEY = %q", r.Header.Get("X-API-KEY"))
+		}
+		fmt.Fprint(w, `{"address": "`+testAddress+`", "score": null, "error": "Invalid address"}`)
+	})
+	f := newTestFetcher(t, srv, WithAPIKey(GITCOIN, "test-key"), WithGitcoinScorer("335"))
+
+	entry := f.processGitcoin(context.Background(), testAddress)
+	if entry.Err == nil || entry.Msg != "[processGitcoin] fetch passport score failed" {
+		t.Errorf("entry = %+v, want passport error", entry)
+	}
+	if entry.Gitcoin == nil || len(entry.Gitcoin.Contributions) != 2 || entry.Gitcoin.Score != 0 || len(entry.Gitcoin.Stamps) != 0 {
+		t.Errorf("Gitcoin = %+v, want the contributions without a score", entry.Gitcoin)
+	}
+}
+
+func TestGitcoinPassportFailureKeepsContributions(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Handle("/v2/stamps/335/score/"+testAddress, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	f := newTestFetcher(t, srv, WithAPIKey(GITCOIN, "test-key"), WithGitcoinScorer("335"))
+
+	ids, _ := f.FetchIdentity(testAddress)
+	if len(ids.Gitcoin) != 1 || len(ids.Gitcoin[0].Contributions) != 2 {
+		t.Errorf("Gitcoin = %+v, want the contributions", ids.Gitcoin)
+	}
+	if status := statusOf(t, ids.Status, GITCOIN); status.State != SourceError {
+		t.Errorf("Gitcoin status = %+v, want the passport failure reported", status)
+	}
+}
+
+func TestParseGitcoinTime(t *testing.T) {
+	for _, s := range []string{"2023-08-22T14:47:59", "2023-08-22T14:47:59Z", "2023-08-22T16:47:59+02:00"} {
+		got, err := parseGitcoinTime(s)
+		if err != nil || got.UTC().Format("2006-01-02T15:04:05") != "2023-08-22T14:47:59" {
+			t.Errorf("parseGitcoinTime(%q) = %v, %v", s, got, err)
+		}
+	}
+}
@@ -1,37 +1,80 @@
 package fetcher
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"go.uber.org/zap"
 )
 
-const IdentityApiCount = 6
+type identityResult struct {
+	source string
+	entry  IdentityEntry
+	status SourceStatus
+}
 
 func (f *fetcher) FetchIdentity(address string) (IdentityEntryList, error) {
+	return f.FetchIdentityWithContext(context.Background(), address)
+}
+
+// FetchIdentityWithContext fans out to every identity source with ctx bound to their requests
+// input is an Ethereum address or an ENS name, names are resolved first and reported in IdentityEntryList.Name
+// if ctx is done before all sources have answered, the entries merged so far are returned with ctx.Err()
+// a failed source is reported as SourceError in IdentityEntryList.Status, the partial data it found is merged
+// IdentityEntryList.Status reports the outcome of every source, including those that never answered
+// once a TwitterLookup is configured, the Twitter handles the sources found are enriched into IdentityEntryList.TwitterProfiles
+func (f *fetcher) FetchIdentityWithContext(ctx context.Context, input string) (IdentityEntryList, error) {
 
 	var identityArr IdentityEntryList
-	ch := make(chan IdentityEntry)
-
-	// Part 1 - Demo data source
-	// Context API
-	go f.processContext(address, ch)
-	// Superrare API
-	go f.processSuperrare(address, ch)
-	// Part 2 - Add other data source here
-	go f.processFoundationNonSocial(address, ch)
-	go f.processOpenSea(address, ch)
-	go f.processZora(address, ch)
-	go f.processRarible(address, ch)
-	// TODO
+	address, name, err := f.resolveInput(ctx, input)
+	identityArr.Address, identityArr.Name = address, name
+	if err != nil {
+		return identityArr, err
+	}
+
+	sources := f.enabledIdentitySources()
+	// buffered so that late workers never block once we stop receiving
+	ch := make(chan identityResult, len(sources))
+	ctx = context.WithValue(ctx, identityMemoKey{}, &identityMemo{results: make(map[string]*memoResult)})
+
+	start := time.Now()
+	// whether identityArr.Ens holds a name verified on-chain, it then wins over the Context name
+	var ensVerified bool
+	pending := make(map[string]bool)
+	for _, src := range sources {
+		pending[src.Name()] = true
+		go func(src IdentitySource) {
+			ch <- f.queryIdentitySourceOnce(ctx, src, address)
+		}(src)
+	}
 
 	// Final Part - Merge entry
-	for i := 0; i < IdentityApiCount; i++ {
-		entry := <-ch
+	for i := 0; i < len(sources); i++ {
+		var res identityResult
+		select {
+		case res = <-ch:
+		case <-ctx.Done():
+			zap.L().With(zap.Error(ctx.Err())).Warn("identity fetch stopped before all sources answered")
+			for _, src := range sources {
+				if pending[src.Name()] {
+					identityArr.Status = append(identityArr.Status, pendingSourceStatus(src.Name(), ctx, time.Since(start)))
+				}
+			}
+			return identityArr, ctx.Err()
+		}
+		delete(pending, res.source)
+		identityArr.Status = append(identityArr.Status, res.status)
+		entry := res.entry
 		if entry.Err != nil {
+			// a source failing part way still hands back what it found, e.g. the explicit Tezos links
 			zap.L().With(zap.Error(entry.Err)).Error("identity api error: " + entry.Msg)
-			continue
 		}
 		if entry.OpenSea != nil {
 			identityArr.OpenSea = append(identityArr.OpenSea, *entry.OpenSea)
@@ -61,34 +104,209 @@ func (f *fetcher) FetchIdentity(address string) (IdentityEntryList, error) {
 		if entry.Showtime != nil {
 			identityArr.Showtime = append(identityArr.Showtime, *entry.Showtime)
 		}
+		if entry.Convo != nil {
+			identityArr.Convo = append(identityArr.Convo, *entry.Convo)
+		}
+		identityArr.Lens = append(identityArr.Lens, entry.Lens...)
+		if entry.Farcaster != nil {
+			identityArr.Farcaster = append(identityArr.Farcaster, *entry.Farcaster)
+		}
+		identityArr.Poap = append(identityArr.Poap, entry.Poap...)
+		identityArr.Mirror = append(identityArr.Mirror, entry.Mirror...)
+		if entry.Snapshot != nil {
+			identityArr.Snapshot = append(identityArr.Snapshot, *entry.Snapshot)
+		}
+		identityArr.Tezos = append(identityArr.Tezos, entry.Tezos...)
+		if entry.Gitcoin != nil {
+			identityArr.Gitcoin = append(identityArr.Gitcoin, *entry.Gitcoin)
+		}
 		if entry.Ens != nil {
-			identityArr.Ens = entry.Ens.Ens
+			identityArr.EnsProfile = append(identityArr.EnsProfile, *entry.Ens)
+			// anyone can point a reverse record at any name, a name resolving back to the address is preferred
+			// and an unverified on-chain name never becomes primary, Context remains the fallback
+			switch {
+			case entry.Ens.Verified:
+				identityArr.Ens = entry.Ens.Ens
+				ensVerified = true
+			case entry.Ens.DataSource == CONTEXT && !ensVerified:
+				identityArr.Ens = entry.Ens.Ens
+			}
+			identityArr.Names = mergeName(identityArr.Names, UserName{
+				Name:       entry.Ens.Ens,
+				Registry:   ENS,
+				Primary:    entry.Ens.Verified,
+				Reverse:    entry.Ens.Reverse,
+				Expires:    entry.Ens.Expires,
+				DataSource: entry.Ens.DataSource,
+			})
+		}
+		for _, name := range entry.Names {
+			identityArr.Names = mergeName(identityArr.Names, name)
+		}
+		if entry.Custom != nil {
+			if identityArr.Custom == nil {
+				identityArr.Custom = make(map[string]interface{})
+			}
+			identityArr.Custom[res.source] = entry.Custom
+		}
+	}
+
+	f.enrichTwitter(ctx, &identityArr)
+	return identityArr, f.checkRequiredSources(identityArr.Status)
+}
+
+// identityMemo shares the result of every source within one FetchIdentity call, so a source relying on
+// another one, e.g. TEZOS on SHOWTIME, does not query it a second time
+type identityMemo struct {
+	mu      sync.Mutex
+	results map[string]*memoResult
+}
+
+type memoResult struct {
+	once sync.Once
+	res  identityResult
+}
+
+type identityMemoKey struct{}
+
+// queryIdentitySourceOnce is queryIdentitySource run at most once per source within a FetchIdentity call
+func (f *fetcher) queryIdentitySourceOnce(ctx context.Context, src IdentitySource, address string) identityResult {
+	memo, ok := ctx.Value(identityMemoKey{}).(*identityMemo)
+	if !ok {
+		return f.queryIdentitySource(ctx, src, address)
+	}
+	memo.mu.Lock()
+	r, ok := memo.results[src.Name()]
+	if !ok {
+		r = &memoResult{}
+		memo.results[src.Name()] = r
+	}
+	memo.mu.Unlock()
+
+	r.once.Do(func() {
+		r.res = f.queryIdentitySource(ctx, src, address)
+	})
+	return r.res
+}
+
+// queryIdentitySource serves src from the cache when possible, otherwise runs it behind its circuit breaker,
+// and reports how it went
+func (f *fetcher) queryIdentitySource(ctx context.Context, src IdentitySource, address string) identityResult {
+	stats := &requestStats{}
+	begin := time.Now()
+	key := cacheKey("identity", src.Name(), address)
+	if src.Name() == TEZOS && len(tezosCallLinks(ctx)) != 0 {
+		// links given for this call change the result, it is neither served from nor stored in the cache
+		key = ""
+	}
+
+	var entry IdentityEntry
+	state := cacheMiss
+	if key != "" {
+		state = f.cacheGet(src.Name(), key, &entry)
+	}
+	switch state {
+	case cacheStale:
+		f.refreshInBackground(key, func(ctx context.Context) {
+			f.fetchIdentityEntry(ctx, src, address, key)
+		})
+	case cacheMiss:
+		entry = f.fetchIdentityEntry(withRequestStats(ctx, stats), src, address, key)
+	}
+
+	status := newSourceStatus(src.Name(), entry.Err, entry.Msg, entry.isEmpty(), time.Since(begin), stats)
+	status.CacheHit = state != cacheMiss
+	status.Stale = state == cacheStale
+	return identityResult{
+		source: src.Name(),
+		entry:  entry,
+		status: status,
+	}
+}
+
+// fetchIdentityEntry runs src behind its circuit breaker and caches successful results under key, "" to leave them out
+func (f *fetcher) fetchIdentityEntry(ctx context.Context, src IdentitySource, address, key string) IdentityEntry {
+	b := f.breaker(src.Name())
+	if b != nil && !b.allow() {
+		return IdentityEntry{
+			Err: ErrSourceUnavailable,
+			Msg: "[" + src.Name() + "] skipped, circuit breaker open",
 		}
 	}
 
-	return identityArr, nil
+	entry := src.FetchIdentity(ctx, address)
+	if b != nil {
+		b.record(breakerOutcomeOf(ctx, entry.Err))
+	}
+	if entry.Err == nil && key != "" {
+		f.cacheSet(src.Name(), key, entry, entry.isEmpty())
+	}
+	return entry
 }
 
-func (f *fetcher) processContext(address string, ch chan<- IdentityEntry) {
+// mergeName adds name to names, a name reported by several sources is kept once with the flags of all of them,
+// names stays sorted primary names first, then by registry and name
+func mergeName(names []UserName, name UserName) []UserName {
+	for i := range names {
+		if names[i].Registry == name.Registry && strings.EqualFold(names[i].Name, name.Name) {
+			merged := &names[i]
+			if name.Reverse && !merged.Reverse {
+				// the source that read the reverse record is the authoritative one
+				merged.DataSource = name.DataSource
+			}
+			merged.Primary = merged.Primary || name.Primary
+			merged.Reverse = merged.Reverse || name.Reverse
+			if merged.Expires.IsZero() {
+				merged.Expires = name.Expires
+			}
+			name = *merged
+			names = append(names[:i], names[i+1:]...)
+			break
+		}
+	}
+	i := sort.Search(len(names), func(i int) bool {
+		n := names[i]
+		if n.Primary != name.Primary {
+			return name.Primary
+		}
+		if n.Registry != name.Registry {
+			return n.Registry > name.Registry
+		}
+		return n.Name >= name.Name
+	})
+	names = append(names, UserName{})
+	copy(names[i+1:], names[i:])
+	names[i] = name
+	return names
+}
+
+// isEmpty reports whether the source found nothing for the address
+func (e IdentityEntry) isEmpty() bool {
+	return e.OpenSea == nil && e.Twitter == nil && e.Superrare == nil && e.Rarible == nil && e.Context == nil &&
+		e.Zora == nil && e.Ens == nil && e.Foundation == nil && e.FoundationNonSocial == nil && e.Showtime == nil &&
+		e.Convo == nil && len(e.Lens) == 0 && e.Farcaster == nil && len(e.Poap) == 0 && len(e.Mirror) == 0 &&
+		e.Snapshot == nil && len(e.Tezos) == 0 && e.Gitcoin == nil && len(e.Names) == 0 && e.Custom == nil
+}
+
+func (f *fetcher) processContext(ctx context.Context, address string) IdentityEntry {
 	var result IdentityEntry
 
-	body, err := sendRequest(f.httpClient, RequestArgs{
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: CONTEXT,
 		url:    fmt.Sprintf(ContextUrl, address),
 		method: "GET",
 	})
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processContext] fetch identity failed"
-		ch <- result
-		return
+		return result
 	}
 	contextProfile := ContextAppResp{}
 	err = json.Unmarshal(body, &contextProfile)
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processContext] identity response json unmarshal failed"
-		ch <- result
-		return
+		return result
 	}
 
 	if value, ok := contextProfile.Ens[address]; ok {
@@ -143,22 +361,21 @@ func (f *fetcher) processContext(address string, ch chan<- IdentityEntry) {
 		}
 	}
 
-	ch <- result
-	return
+	return result
 }
 
-func (f *fetcher) processSuperrare(address string, ch chan<- IdentityEntry) {
+func (f *fetcher) processSuperrare(ctx context.Context, address string) IdentityEntry {
 	var result IdentityEntry
 
-	body, err := sendRequest(f.httpClient, RequestArgs{
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: SUPERRARE,
 		url:    fmt.Sprintf(SuperrareUrl, address),
 		method: "GET",
 	})
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processSuperrare] fetch identity failed"
-		ch <- result
-		return
+		return result
 	}
 
 	sprProfile := SuperrareProfile{}
@@ -166,8 +383,7 @@ func (f *fetcher) processSuperrare(address string, ch chan<- IdentityEntry) {
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processSuperrare] identity response json unmarshal failednti"
-		ch <- result
-		return
+		return result
 	}
 
 	newSprRecord := UserSuperrareIdentity{
@@ -189,12 +405,12 @@ func (f *fetcher) processSuperrare(address string, ch chan<- IdentityEntry) {
 		result.Superrare = &newSprRecord
 	}
 
-	ch <- result
+	return result
 }
 
 // processFoundationNonSocial will query the Foundation GraphQL API
 // it will get NFT, ETH Financial and Creator data for an address instead
-func (f *fetcher) processFoundationNonSocial(address string, ch chan<- IdentityEntry) {
+func (f *fetcher) processFoundationNonSocial(ctx context.Context, address string) IdentityEntry {
 	var result IdentityEntry
 
 	// GraphQL query that gets data from an account that matches the address
@@ -230,21 +446,22 @@ func (f *fetcher) processFoundationNonSocial(address string, ch chan<- IdentityE
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processFoundationNonSocial] marshalling GraphQL query to JSON failed"
-		ch <- result
-		return
+		return result
 	}
 
 	// sending a POST request which contains the GraphQL query in the body
-	body, err := sendRequest(f.httpClient, RequestArgs{
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: FOUNDATION,
 		url:    FoundationUrl,
 		method: "POST",
 		body:   jsonQuery,
+		// GraphQL queries only read data
+		idempotent: true,
 	})
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processFoundationNonSocial] fetch identity failed"
-		ch <- result
-		return
+		return result
 	}
 
 	fndProfile := FoundationProfileNonSocial{}
@@ -252,8 +469,7 @@ func (f *fetcher) processFoundationNonSocial(address string, ch chan<- IdentityE
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processFoundationNonSocial] identity response JSON unmarshal failed"
-		ch <- result
-		return
+		return result
 	}
 
 	// using Accounts[0] here since the JSON response is an array of accounts but we are only using one address currently
@@ -271,25 +487,111 @@ func (f *fetcher) processFoundationNonSocial(address string, ch chan<- IdentityE
 	if len(newFndRecord.Nfts) != 0 || len(newFndRecord.Withdrawals) != 0 || newFndRecord.NetRevenueInETH != "0" || newFndRecord.IsAdmin != false {
 		result.FoundationNonSocial = &newFndRecord
 	}
-	ch <- result
+	return result
+}
+
+// processFoundation will query the GraphQL API behind foundation.app
+// for the profile and the verified social accounts of an address
+func (f *fetcher) processFoundation(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	// users are keyed by their checksummed address
+	gqlQuery := map[string]string{
+		"query": fmt.Sprintf(`{
+			user: user_by_pk(publicKey: "%s") {
+				username,
+				bio,
+				links,
+				twitSocialVerifs: socialVerifications(where: {service: {_eq: "TWITTER"}, isValid: {_eq: true}}) {
+					username
+				}
+				instaSocialVerifs: socialVerifications(where: {service: {_eq: "INSTAGRAM"}, isValid: {_eq: true}}) {
+					username
+				}
+			}
+		}
+		`, common.HexToAddress(address).Hex()),
+	}
+
+	jsonQuery, err := json.Marshal(gqlQuery)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processFoundation] marshalling GraphQL query to JSON failed"
+		return result
+	}
+
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: FOUNDATION_SOCIAL,
+		url:    FoundationSocialUrl,
+		method: "POST",
+		body:   jsonQuery,
+		// GraphQL queries only read data
+		idempotent: true,
+	})
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processFoundation] fetch identity failed"
+		return result
+	}
+
+	fndProfile := FoundationIdentity{}
+	err = json.Unmarshal(body, &fndProfile)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processFoundation] identity response JSON unmarshal failed"
+		return result
+	}
+
+	user := fndProfile.Data.User
+	newFndRecord := UserFoundationIdentity{
+		Username:   user.Username,
+		Bio:        user.Bio,
+		Tiktok:     user.Links.Tiktok.Handle,
+		Twitch:     user.Links.Twitch.Handle,
+		Discord:    user.Links.Discord.Handle,
+		Twitter:    user.Links.Twitter.Handle,
+		Website:    user.Links.Website.Handle,
+		Youtube:    user.Links.Youtube.Handle,
+		Facebook:   user.Links.Facebook.Handle,
+		Snapchat:   user.Links.Snapchat.Handle,
+		Instagram:  user.Links.Instagram.Handle,
+		DataSource: FOUNDATION,
+	}
+	if len(user.TwitSocialVerifs) > 0 {
+		newFndRecord.VerifiedTwitter = user.TwitSocialVerifs[0].Username
+	}
+	if len(user.InstaSocialVerifs) > 0 {
+		newFndRecord.VerifiedInstagram = user.InstaSocialVerifs[0].Username
+	}
+
+	if newFndRecord != (UserFoundationIdentity{DataSource: FOUNDATION}) {
+		result.Foundation = &newFndRecord
+	}
+	return result
 }
 
 // processOpenSea will query the OpenSea HTTPS API for data on an address
 // currently the data being pulled is user data like PFP image URL, NFTs owned, etc
 // The OpenSea API is rate-limited and may require an API key in production environments
-func (f *fetcher) processOpenSea(address string, ch chan<- IdentityEntry) {
+func (f *fetcher) processOpenSea(ctx context.Context, address string) IdentityEntry {
 	var result IdentityEntry
 
+	var header map[string]string
+	if key := f.apiKey(OPENSEA); key != "" {
+		header = map[string]string{"X-API-KEY": key}
+	}
+
 	// pulling OpenSea account data for this address
-	accBody, err := sendRequest(f.httpClient, RequestArgs{
+	accBody, err := f.sendRequest(ctx, RequestArgs{
+		source: OPENSEA,
 		url:    fmt.Sprintf("%s/account/%s", OpenSeaUrl, address),
 		method: "GET",
+		header: header,
 	})
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processOpenSea] fetch account identity failed"
-		ch <- result
-		return
+		return result
 	}
 
 	accSeaProfile := OpenSeaProfileAccount{}
@@ -297,20 +599,20 @@ func (f *fetcher) processOpenSea(address string, ch chan<- IdentityEntry) {
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processOpenSea] account identity response json unmarshal failed"
-		ch <- result
-		return
+		return result
 	}
 
 	// pulling data on owned assets(NFTs) this address is an owner of
-	nftBody, err := sendRequest(f.httpClient, RequestArgs{
+	nftBody, err := f.sendRequest(ctx, RequestArgs{
+		source: OPENSEA,
 		url:    fmt.Sprintf("%s/assets?owner=%s", OpenSeaUrl, address),
 		method: "GET",
+		header: header,
 	})
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processOpenSea] fetch NFT identity failed"
-		ch <- result
-		return
+		return result
 	}
 
 	nftSeaProfile := OpenSeaProfileNft{}
@@ -318,8 +620,7 @@ func (f *fetcher) processOpenSea(address string, ch chan<- IdentityEntry) {
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processOpenSea] NFT identity response JSON unmarshal failed"
-		ch <- result
-		return
+		return result
 	}
 
 	newSeaRecord := UserOpenSeaIdentity{
@@ -331,12 +632,12 @@ func (f *fetcher) processOpenSea(address string, ch chan<- IdentityEntry) {
 	if len(newSeaRecord.Assets) != 0 || newSeaRecord.Username != "" || newSeaRecord.ProfileImageUrl != "" {
 		result.OpenSea = &newSeaRecord
 	}
-	ch <- result
+	return result
 }
 
 // processZora will query the Zora GraphQL API for data on an address
 // it will pull data related to media(NFTs) both created/owned and bids
-func (f *fetcher) processZora(address string, ch chan<- IdentityEntry) {
+func (f *fetcher) processZora(ctx context.Context, address string) IdentityEntry {
 	var result IdentityEntry
 
 	zoraMediaQuery := `
@@ -379,21 +680,22 @@ func (f *fetcher) processZora(address string, ch chan<- IdentityEntry) {
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processZora] marshalling GraphQL query to JSON failed"
-		ch <- result
-		return
+		return result
 	}
 
 	// sending a POST request which contains the GraphQL query in the body
-	body, err := sendRequest(f.httpClient, RequestArgs{
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: ZORA,
 		url:    ZoraUrl,
 		method: "POST",
 		body:   jsonQuery,
+		// GraphQL queries only read data
+		idempotent: true,
 	})
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processZora] fetch identity failed"
-		ch <- result
-		return
+		return result
 	}
 
 	zoraProfile := ZoraProfile{}
@@ -401,8 +703,7 @@ func (f *fetcher) processZora(address string, ch chan<- IdentityEntry) {
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processZora] identity response JSON unmarshal failed"
-		ch <- result
-		return
+		return result
 	}
 
 	// using Users[0] here since the JSON response is an array of accounts but we are only using one address currently
@@ -418,27 +719,27 @@ func (f *fetcher) processZora(address string, ch chan<- IdentityEntry) {
 	if len(newZoraRecord.Collection) != 0 || len(newZoraRecord.Creations) != 0 || len(newZoraRecord.CurrentBids) != 0 {
 		result.Zora = &newZoraRecord
 	}
-	ch <- result
+	return result
 }
 
 // processRarible will query the Rarible HTTPS API for an address
 // it will pull data related to NFT collections both created/owned, ETH financial and bid data
-func (f *fetcher) processRarible(address string, ch chan<- IdentityEntry) {
+func (f *fetcher) processRarible(ctx context.Context, address string) IdentityEntry {
 	var result IdentityEntry
 
 	// Rarible API supports chains like POLYGON etc., so here we must specify ETHEREUM
 	address = fmt.Sprintf("ETHEREUM:%s", address)
 
 	// pulling data on NFTs this address is an owner of
-	itemOwnerBody, err := sendRequest(f.httpClient, RequestArgs{
+	itemOwnerBody, err := f.sendRequest(ctx, RequestArgs{
+		source: RARIBLE,
 		url:    fmt.Sprintf("%s/items/byOwner?owner=%s", RaribleUrl, address),
 		method: "GET",
 	})
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processRarible] fetch item owner data failed"
-		ch <- result
-		return
+		return result
 	}
 
 	itemOwnerProfile := RaribleItemProfile{}
@@ -446,20 +747,19 @@ func (f *fetcher) processRarible(address string, ch chan<- IdentityEntry) {
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processRarible] item owner data response JSON unmarshal failed"
-		ch <- result
-		return
+		return result
 	}
 
 	// pulling data on NFTs this address is a creator of
-	itemCreatorBody, err := sendRequest(f.httpClient, RequestArgs{
+	itemCreatorBody, err := f.sendRequest(ctx, RequestArgs{
+		source: RARIBLE,
 		url:    fmt.Sprintf("%s/items/byCreator?creator=%s", RaribleUrl, address),
 		method: "GET",
 	})
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processRarible] fetch item creator data failed"
-		ch <- result
-		return
+		return result
 	}
 
 	itemCreatorProfile := RaribleItemProfile{}
@@ -467,20 +767,19 @@ func (f *fetcher) processRarible(address string, ch chan<- IdentityEntry) {
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processRarible] item creator data response JSON unmarshal failed"
-		ch <- result
-		return
+		return result
 	}
 
 	// get data on a users Rarible NFT activities such as transferring, buying, selling, minting etc.
-	userActivityBody, err := sendRequest(f.httpClient, RequestArgs{
+	userActivityBody, err := f.sendRequest(ctx, RequestArgs{
+		source: RARIBLE,
 		url:    fmt.Sprintf("%s/activities/byUser/?user=%s&type=BUY,SELL,TRANSFER_FROM,TRANSFER_TO,MINT,BURN", RaribleUrl, address),
 		method: "GET",
 	})
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processRarible] fetch user activity data failed"
-		ch <- result
-		return
+		return result
 	}
 
 	userActivityProfile := RaribleUserActivityProfile{}
@@ -488,8 +787,7 @@ func (f *fetcher) processRarible(address string, ch chan<- IdentityEntry) {
 	if err != nil {
 		result.Err = err
 		result.Msg = "[processRarible] user activity data response JSON unmarshal failed"
-		ch <- result
-		return
+		return result
 	}
 
 	newRaribleRecord := UserRaribleIdentity{
@@ -501,5 +799,113 @@ func (f *fetcher) processRarible(address string, ch chan<- IdentityEntry) {
 	if len(newRaribleRecord.Owned.Items) != 0 || len(newRaribleRecord.Created.Items) != 0 || len(newRaribleRecord.Activities) != 0 {
 		result.Rarible = &newRaribleRecord
 	}
-	ch <- result
+	return result
+}
+
+// processShowtime will query the Showtime profile API for the profile and the social links of an address
+func (f *fetcher) processShowtime(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: SHOWTIME,
+		url:    fmt.Sprintf(ShowtimeUrl, address),
+		method: "GET",
+	})
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processShowtime] fetch identity failed"
+		return result
+	}
+
+	showtimeProfile := ShowtimeProfile{}
+	err = json.Unmarshal(body, &showtimeProfile)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processShowtime] identity response json unmarshal failed"
+		return result
+	}
+
+	profile := showtimeProfile.Data.Profile
+	newShowtimeRecord := UserShowtimeIdentity{
+		Name:       profile.Name,
+		Username:   profile.Username,
+		Bio:        profile.Bio,
+		DataSource: SHOWTIME,
+	}
+	for _, link := range profile.Links {
+		handle := strings.TrimSpace(link.UserInput)
+		// link types are matched on their display name, e.g. "Hic et Nunc" or "CryptoArt.ai"
+		switch typeName := strings.ToLower(strings.Join(strings.Fields(link.TypeName), "")); {
+		case typeName == "twitter":
+			newShowtimeRecord.TwitterHandle = handle
+		case typeName == "linktree":
+			newShowtimeRecord.LinkTreeHandle = handle
+		case strings.HasPrefix(typeName, "cryptoart"):
+			newShowtimeRecord.CryptoArtHandle = handle
+		case typeName == "foundation":
+			newShowtimeRecord.FoundationHandle = handle
+		case typeName == "hicetnunc":
+			newShowtimeRecord.HicetnuncHandle = handle
+		case typeName == "opensea":
+			newShowtimeRecord.OpenseaHandle = handle
+		case typeName == "rarible":
+			newShowtimeRecord.RaribleHandle = handle
+		}
+	}
+
+	if newShowtimeRecord != (UserShowtimeIdentity{DataSource: SHOWTIME}) {
+		result.Showtime = &newShowtimeRecord
+	}
+	return result
+}
+
+// convoAPIKey returns the Convo key configured with WithAPIKey, the shared public key otherwise
+func (f *fetcher) convoAPIKey() string {
+	if key := f.apiKey(CONVO); key != "" {
+		return key
+	}
+	return ConvoPublicAPIKey
+}
+
+func (f *fetcher) processConvo(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: CONVO,
+		url:    ConvoUrl + "/identity",
+		method: "GET",
+		params: map[string]string{"address": address, "apikey": f.convoAPIKey()},
+	})
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processConvo] fetch identity failed"
+		return result
+	}
+
+	convoProfile := ConvoIdentity{}
+	err = json.Unmarshal(body, &convoProfile)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processConvo] identity response json unmarshal failed"
+		return result
+	}
+	if !convoProfile.Success {
+		result.Err = errors.New("convo api reported failure")
+		result.Msg = "[processConvo] fetch identity failed"
+		return result
+	}
+
+	newConvoRecord := UserConvoIdentity{
+		DisplayName: convoProfile.Data.DisplayName,
+		Bio:         convoProfile.Data.Bio,
+		Twitter:     convoProfile.Data.Links.Twitter,
+		Github:      convoProfile.Data.Links.Github,
+		Discord:     convoProfile.Data.Links.Discord,
+		Website:     convoProfile.Data.Links.Website,
+		DataSource:  CONVO,
+	}
+	if newConvoRecord != (UserConvoIdentity{DataSource: CONVO}) {
+		result.Convo = &newConvoRecord
+	}
+	return result
 }
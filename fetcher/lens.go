@@ -0,0 +1,175 @@
+package fetcher
+
+import (
+	"context"
+	"strings"
+)
+
+// lensPageSize is the largest page the Lens API serves
+const lensPageSize = 50
+
+const lensProfilesQuery = `query Profiles($ownedBy: [EthereumAddress!], $limit: LimitScalar!, $cursor: Cursor) {
+	profiles(request: {ownedBy: $ownedBy, limit: $limit, cursor: $cursor}) {
+		items {
+			id
+			handle
+			name
+			bio
+			attributes { key value }
+			picture {
+				... on MediaSet { original { url } }
+				... on NftImage { uri }
+			}
+			stats { totalFollowers totalFollowing }
+		}
+		pageInfo { next }
+	}
+}`
+
+const lensFollowingQuery = `query Following($address: EthereumAddress!, $limit: LimitScalar!, $cursor: Cursor) {
+	following(request: {address: $address, limit: $limit, cursor: $cursor}) {
+		items { profile { ownedBy } }
+		pageInfo { next }
+	}
+}`
+
+const lensFollowersQuery = `query Followers($profileId: ProfileId!, $limit: LimitScalar!, $cursor: Cursor) {
+	followers(request: {profileId: $profileId, limit: $limit, cursor: $cursor}) {
+		items { wallet { address } }
+		pageInfo { next }
+	}
+}`
+
+// lensProfiles pages through every profile owned by address
+func (f *fetcher) lensProfiles(ctx context.Context, address string) ([]UserLensIdentity, error) {
+	var profiles []UserLensIdentity
+	var cursor interface{}
+	for {
+		var page LensProfiles
+		err := f.graphQLQuery(ctx, LENS, LensUrl, lensProfilesQuery, map[string]interface{}{"ownedBy": []string{address}, "limit": lensPageSize, "cursor": cursor}, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Profiles.Items {
+			profile := UserLensIdentity{
+				ProfileID:      item.ID,
+				Handle:         item.Handle,
+				Name:           item.Name,
+				Bio:            item.Bio,
+				Avatar:         item.Picture.Original.URL,
+				TotalFollowers: item.Stats.TotalFollowers,
+				TotalFollowing: item.Stats.TotalFollowing,
+				DataSource:     LENS,
+			}
+			if profile.Avatar == "" {
+				profile.Avatar = item.Picture.URI
+			}
+			if len(item.Attributes) > 0 {
+				profile.Attributes = make(map[string]string, len(item.Attributes))
+				for _, attr := range item.Attributes {
+					profile.Attributes[attr.Key] = attr.Value
+				}
+			}
+			profiles = append(profiles, profile)
+		}
+		if page.Profiles.PageInfo.Next == "" || len(page.Profiles.Items) < lensPageSize {
+			return profiles, nil
+		}
+		cursor = page.Profiles.PageInfo.Next
+	}
+}
+
+// lensEdges pages through a following or followers query, field, until exhaustion or until the counterparts
+// collected reach maxEdges, 0 for no cap
+func (f *fetcher) lensEdges(ctx context.Context, query, field string, variables map[string]interface{}, counterparts []string, maxEdges int) ([]string, bool, error) {
+	var cursor interface{}
+	for {
+		variables["limit"] = lensPageSize
+		variables["cursor"] = cursor
+		var page map[string]LensEdges
+		if err := f.graphQLQuery(ctx, LENS, LensUrl, query, variables, &page); err != nil {
+			return nil, false, err
+		}
+		edges := page[field]
+		for i, item := range edges.Items {
+			if maxEdges > 0 && len(counterparts) >= maxEdges {
+				return counterparts, true, nil
+			}
+			counterpart := item.Profile.OwnedBy
+			if counterpart == "" {
+				counterpart = item.Wallet.Address
+			}
+			counterparts = append(counterparts, strings.ToLower(counterpart))
+			if i == len(edges.Items)-1 && maxEdges > 0 && len(counterparts) >= maxEdges {
+				// a further page means edges were left out
+				return counterparts, edges.PageInfo.Next != "" && len(edges.Items) == lensPageSize, nil
+			}
+		}
+		if edges.PageInfo.Next == "" || len(edges.Items) < lensPageSize {
+			return counterparts, false, nil
+		}
+		cursor = edges.PageInfo.Next
+	}
+}
+
+func (f *fetcher) processLens(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	profiles, err := f.lensProfiles(ctx, address)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processLens] fetch profiles failed"
+		return result
+	}
+	result.Lens = profiles
+	return result
+}
+
+// processLensConn fetches who address follows and who follows any of the profiles it owns
+func (f *fetcher) processLensConn(ctx context.Context, address string) ConnectionEntryList {
+	result := ConnectionEntryList{}
+	maxEdges := f.maxEdges(LENS)
+
+	followings, truncated, err := f.lensEdges(ctx, lensFollowingQuery, "following",
+		map[string]interface{}{"address": address}, nil, maxEdges)
+	if err != nil {
+		result.Err = err
+		result.msg = "[processLensConn] fetch Lens followings failed"
+		return result
+	}
+	result.Truncated = truncated
+
+	// followers are per profile, so the profiles owned by address come first
+	profiles, err := f.lensProfiles(ctx, address)
+	if err != nil {
+		result.Err = err
+		result.msg = "[processLensConn] fetch Lens profiles failed"
+		return result
+	}
+	var followers []string
+	for _, profile := range profiles {
+		followers, truncated, err = f.lensEdges(ctx, lensFollowersQuery, "followers",
+			map[string]interface{}{"profileId": profile.ProfileID}, followers, maxEdges)
+		if err != nil {
+			result.Err = err
+			result.msg = "[processLensConn] fetch Lens followers failed"
+			return result
+		}
+		if truncated {
+			result.Truncated = true
+			break
+		}
+	}
+
+	for _, to := range followings {
+		if addressFilter(to) {
+			result.Conn = append(result.Conn, ConnectionEntry{From: address, To: to, Platform: LENS})
+		}
+	}
+	for _, from := range followers {
+		if addressFilter(from) {
+			result.Conn = append(result.Conn, ConnectionEntry{From: from, To: address, Platform: LENS})
+		}
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// lensPager answers the Lens GraphQL queries with following pages over n edges and no followers
+func lensPager(t *testing.T, n int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding GraphQL request: %v", err)
+		}
+		offset := 0
+		if cursor, ok := req.Variables["cursor"].(string); ok {
+			offset, _ = strconv.Atoi(cursor)
+		}
+
+		switch {
+		case req.Variables["ownedBy"] != nil:
+			fmt.Fprint(w, `{"data": {"profiles": {"items": [{"id": "0x01", "handle": "paged.lens"}], "pageInfo": {"next": "1"}}}}`)
+		case req.Variables["profileId"] != nil:
+			fmt.Fprint(w, `{"data": {"followers": {"items": [], "pageInfo": {"next": null}}}}`)
+		default:
+			var items []string
+			for i := offset; i < n && i < offset+lensPageSize; i++ {
+				items = append(items, fmt.Sprintf(`{"profile": {"ownedBy": "0x%040x"}}`, i+1))
+			}
+			fmt.Fprintf(w, `{"data": {"following": {"items": [%s], "pageInfo": {"next": "%d"}}}}`,
+				strings.Join(items, ","), offset+len(items))
+		}
+	}
+}
+
+func TestLensConnectionsPagination(t *testing.T) {
+	tests := []struct {
+		edges, maxEdges int
+		want            int
+		truncated       bool
+	}{
+		{120, 0, 120, false},
+		{120, 70, 70, true},
+		{120, 100, 100, true},
+		{120, 120, 120, false},
+		{DefaultMaxEdges + 10, -1, DefaultMaxEdges + 10, false},
+	}
+	for _, tt := range tests {
+		srv := newTestServer(t)
+		srv.Handle("/lens/", lensPager(t, tt.edges))
+		opts := []Option{WithBaseURL(LENS, srv.URL+"/lens"), WithRetryPolicy(testRetryPolicy)}
+		if tt.maxEdges != 0 {
+			opts = append(opts, WithMaxEdges(LENS, tt.maxEdges))
+		}
+		f := NewFetcher(opts...)
+
+		entry := f.processLensConn(context.Background(), testAddress)
+		if entry.Err != nil {
+			t.Fatal(entry.Err)
+		}
+		if len(entry.Conn) != tt.want || entry.Truncated != tt.truncated {
+			t.Errorf("%d edges with cap %d: got %d edges, truncated %v, want %d, %v",
+				tt.edges, tt.maxEdges, len(entry.Conn), entry.Truncated, tt.want, tt.truncated)
+		}
+	}
+}
+
+func TestLensTruncatedStatus(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Handle("/lens/", lensPager(t, 60))
+	f := NewFetcher(
+		WithBaseURL(LENS, srv.URL+"/lens"),
+		WithMaxEdges(LENS, 10),
+		onlySources(LENS),
+	)
+
+	conn, err := f.FetchConnectionsWithContext(context.Background(), testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := statusOf(t, conn.Status, LENS); !s.Truncated || s.State != SourceSuccess || len(conn.Conn) != 10 {
+		t.Errorf("status = %+v with %d edges, want 10 edges and a truncated result", s, len(conn.Conn))
+	}
+}
+
+func TestLensGraphQLError(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Handle("/lens/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": null, "errors": [{"message": "Rate limited"}]}`)
+	})
+	f := NewFetcher(WithBaseURL(LENS, srv.URL+"/lens"))
+
+	if entry := f.processLens(context.Background(), testAddress); entry.Err == nil || len(entry.Lens) != 0 {
+		t.Errorf("entry = %+v, want the GraphQL error", entry)
+	}
+}
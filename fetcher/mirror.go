@@ -0,0 +1,145 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// mirrorPageSize is the number of Arweave transactions asked per page
+const mirrorPageSize = 100
+
+// mirrorEntryFetches bounds the entries read from Arweave at the same time
+const mirrorEntryFetches = 8
+
+// newest first, so the first transaction seen of a publication is its latest revision
+const mirrorEntriesQuery = `query MirrorEntries($contributor: [String!]!, $first: Int!, $after: String) {
+	transactions(
+		tags: [{name: "App-Name", values: ["MirrorXYZ"]}, {name: "Contributor", values: $contributor}]
+		sort: HEIGHT_DESC
+		first: $first
+		after: $after
+	) {
+		pageInfo { hasNextPage }
+		edges {
+			cursor
+			node {
+				id
+				block { timestamp }
+				tags { name value }
+			}
+		}
+	}
+}`
+
+// getMirrorTransactions pages through the Mirror entries signed by address, keeping the latest revision
+// of every publication keyed by its original content digest
+func (f *fetcher) getMirrorTransactions(ctx context.Context, address string) ([]UserMirrorPublication, error) {
+	var publications []UserMirrorPublication
+	seen := make(map[string]bool)
+	var after interface{}
+	for {
+		var page ArweaveTransactions
+		err := f.graphQLQuery(ctx, MIRROR, ArweaveUrl+"/graphql", mirrorEntriesQuery, map[string]interface{}{
+			// Mirror tags entries with the checksummed address, older entries with the lowercase one
+			"contributor": []string{common.HexToAddress(address).Hex(), strings.ToLower(address)},
+			"first":       mirrorPageSize,
+			"after":       after,
+		}, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		edges := page.Transactions.Edges
+		for _, edge := range edges {
+			publication := UserMirrorPublication{
+				ArweaveTx:  edge.Node.ID,
+				DataSource: MIRROR,
+			}
+			if edge.Node.Block.Timestamp > 0 {
+				publication.PublishedAt = time.Unix(edge.Node.Block.Timestamp, 0).UTC()
+			}
+			for _, tag := range edge.Node.Tags {
+				switch tag.Name {
+				case "Original-Content-Digest":
+					publication.Digest = tag.Value
+				case "Contributor":
+					publication.Contributor = strings.ToLower(tag.Value)
+				}
+			}
+			if publication.Digest == "" || seen[publication.Digest] {
+				continue
+			}
+			seen[publication.Digest] = true
+			publications = append(publications, publication)
+		}
+		if !page.Transactions.PageInfo.HasNextPage || len(edges) == 0 {
+			return publications, nil
+		}
+		after = edges[len(edges)-1].Cursor
+	}
+}
+
+// readMirrorEntry fills in publication from its entry on Arweave
+func (f *fetcher) readMirrorEntry(ctx context.Context, publication *UserMirrorPublication) error {
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: MIRROR,
+		url:    fmt.Sprintf("%s/%s", ArweaveUrl, publication.ArweaveTx),
+		method: "GET",
+	})
+	if err != nil {
+		return err
+	}
+
+	var entry MirrorEntry
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return err
+	}
+	publication.Title = entry.Content.Title
+	if entry.Content.Timestamp > 0 {
+		// the time the writer published at, the block may be mined much later
+		publication.PublishedAt = time.Unix(entry.Content.Timestamp, 0).UTC()
+	}
+	if entry.Authorship.Contributor != "" {
+		publication.Contributor = strings.ToLower(entry.Authorship.Contributor)
+	}
+	return nil
+}
+
+// processMirror lists the publications authored by address, their titles are read from the entries on Arweave,
+// mirrorEntryFetches at a time, a publication whose entry cannot be read is kept without a title
+func (f *fetcher) processMirror(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	publications, err := f.getMirrorTransactions(ctx, address)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processMirror] fetch entries failed"
+		return result
+	}
+
+	slots := make(chan struct{}, mirrorEntryFetches)
+	var wg sync.WaitGroup
+	for i := range publications {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(publication *UserMirrorPublication) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := f.readMirrorEntry(ctx, publication); err != nil {
+				zap.L().With(zap.Error(err), zap.String("tx", publication.ArweaveTx)).
+					Warn("[processMirror] fetch entry content failed")
+			}
+		}(&publications[i])
+	}
+	wg.Wait()
+
+	result.Mirror = publications
+	return result
+}
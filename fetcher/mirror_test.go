@@ -0,0 +1,109 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyberconnecthq/indexer/fetcher/fakeupstream"
+)
+
+func TestMirrorPagination(t *testing.T) {
+	srv := newTestServer(t)
+	var pages []interface{}
+	srv.Handle("/arweave/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		pages = append(pages, req.Variables["after"])
+		if req.Variables["after"] == nil {
+			fmt.Fprint(w, `{"data": {"transactions": {"pageInfo": {"hasNextPage": true}, "edges": [
+				{"cursor": "c1", "node": {"id": "tx1", "tags": [{"name": "Original-Content-Digest", "value": "d1"}]}}]}}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"transactions": {"pageInfo": {"hasNextPage": false}, "edges": [
+			{"cursor": "c2", "node": {"id": "tx2", "tags": [{"name": "Original-Content-Digest", "value": "d2"}]}}]}}}`)
+	})
+	for _, tx := range []string{"tx1", "tx2"} {
+		tx := tx
+		srv.Handle("/arweave/"+tx, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"content": {"title": "title of %s"}}`, tx)
+		})
+	}
+	f := NewFetcher(WithBaseURL(MIRROR, srv.URL+"/arweave"))
+
+	entry := f.processMirror(context.Background(), testAddress)
+	if entry.Err != nil {
+		t.Fatal(entry.Err)
+	}
+	if len(entry.Mirror) != 2 || entry.Mirror[1].Title != "title of tx2" || !strings.HasSuffix(fmt.Sprint(pages), "c1]") {
+		t.Errorf("Mirror = %+v after pages %v", entry.Mirror, pages)
+	}
+}
+
+// mirrorEntries makes srv list n publications tx0..txN-1 under /arweave, entry answers for each of them
+func mirrorEntries(srv *fakeupstream.Server, n int, entry http.HandlerFunc) {
+	var edges []string
+	for i := 0; i < n; i++ {
+		edges = append(edges, fmt.Sprintf(`{"cursor": "c%d", "node": {"id": "tx%d", "tags": [{"name": "Original-Content-Digest", "value": "d%d"}]}}`, i, i, i))
+		srv.Handle(fmt.Sprintf("/arweave/tx%d", i), entry)
+	}
+	srv.Handle("/arweave/graphql", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data": {"transactions": {"pageInfo": {"hasNextPage": false}, "edges": [%s]}}}`, strings.Join(edges, ","))
+	})
+}
+
+func TestMirrorEntryFailureKeepsPublication(t *testing.T) {
+	srv := newTestServer(t)
+	mirrorEntries(srv, 3, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/arweave/tx1" {
+			http.Error(w, "gone", http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"content": {"title": "title of %s"}}`, strings.TrimPrefix(r.URL.Path, "/arweave/"))
+	})
+	f := NewFetcher(WithBaseURL(MIRROR, srv.URL+"/arweave"), WithRetryPolicy(testRetryPolicy))
+
+	entry := f.processMirror(context.Background(), testAddress)
+	if entry.Err != nil {
+		t.Fatal(entry.Err)
+	}
+	if len(entry.Mirror) != 3 || entry.Mirror[0].Title != "title of tx0" || entry.Mirror[1].Title != "" ||
+		entry.Mirror[1].ArweaveTx != "tx1" || entry.Mirror[2].Title != "title of tx2" {
+		t.Errorf("Mirror = %+v, want the publication of tx1 kept without a title", entry.Mirror)
+	}
+}
+
+func TestMirrorEntryFetchesBounded(t *testing.T) {
+	srv := newTestServer(t)
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	mirrorEntries(srv, 3*mirrorEntryFetches, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		fmt.Fprint(w, `{"content": {"title": "t"}}`)
+	})
+	f := NewFetcher(WithBaseURL(MIRROR, srv.URL+"/arweave"))
+
+	entry := f.processMirror(context.Background(), testAddress)
+	if entry.Err != nil || len(entry.Mirror) != 3*mirrorEntryFetches {
+		t.Fatalf("Mirror = %d publications, err %v", len(entry.Mirror), entry.Err)
+	}
+	if peak > mirrorEntryFetches || peak < 2 {
+		t.Errorf("%d entries read at once, want between 2 and %d", peak, mirrorEntryFetches)
+	}
+}
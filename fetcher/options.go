@@ -0,0 +1,186 @@
+package fetcher
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Option configures a fetcher created by NewFetcher
+type Option func(*fetcher)
+
+// sourceConfig holds the per-source overrides set through options
+type sourceConfig struct {
+	baseURL string
+	apiKey  string
+	header  map[string]string
+	// maxEdges is the cap set with WithMaxEdges, 0 when unset and negative for no cap
+	maxEdges int
+}
+
+// DefaultMaxEdges caps the connections a paginated source fetches in each direction, see WithMaxEdges
+const DefaultMaxEdges = 10000
+
+// WithHTTPClient replaces the default HTTP client used for every source
+func WithHTTPClient(client *http.Client) Option {
+	return func(f *fetcher) {
+		f.httpClient = client
+	}
+}
+
+// WithTransport sets the http.RoundTripper of the HTTP client, e.g. to go through an egress proxy
+func WithTransport(transport http.RoundTripper) Option {
+	return func(f *fetcher) {
+		client := *f.httpClient
+		client.Transport = transport
+		f.httpClient = &client
+	}
+}
+
+// WithBaseURL sends the requests of source to baseURL instead of the public API host
+// the scheme and host of every request are replaced and the path of baseURL is prepended to the request path,
+// e.g. WithBaseURL(OPENSEA, "http://127.0.0.1:8080/opensea") turns https://api.opensea.io/api/v1/account/0x...
+// into http://127.0.0.1:8080/opensea/api/v1/account/0x...
+func WithBaseURL(source, baseURL string) Option {
+	return func(f *fetcher) {
+		f.sourceConfig(source).baseURL = baseURL
+	}
+}
+
+// WithAPIKey sets the API key of source, the source decides how it is sent
+// e.g. OpenSea sends it in the X-API-KEY header
+func WithAPIKey(source, key string) Option {
+	return func(f *fetcher) {
+		f.sourceConfig(source).apiKey = key
+	}
+}
+
+// WithHeader adds a header to every request of source
+func WithHeader(source, key, value string) Option {
+	return func(f *fetcher) {
+		cfg := f.sourceConfig(source)
+		if cfg.header == nil {
+			cfg.header = make(map[string]string)
+		}
+		cfg.header[key] = value
+	}
+}
+
+// WithMaxEdges caps the connections source pages through in each direction, followings and followers,
+// n <= 0 removes the cap, the result of a source that stopped at its cap is marked SourceStatus.Truncated
+// the connection sources LENS, FARCASTER, RARIBLE, SNAPSHOT_FOLLOWS and POAP_COATTENDANCE read their cap here,
+// and so does the SNAPSHOT identity source, which caps its followed spaces and its votes, without a Truncated status
+func WithMaxEdges(source string, n int) Option {
+	return func(f *fetcher) {
+		if n <= 0 {
+			n = -1
+		}
+		f.sourceConfig(source).maxEdges = n
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every request
+func WithUserAgent(userAgent string) Option {
+	return func(f *fetcher) {
+		f.userAgent = userAgent
+	}
+}
+
+// WithIdentitySource registers src, replacing the built-in identity source of the same name if any
+func WithIdentitySource(src IdentitySource) Option {
+	return func(f *fetcher) {
+		for i, s := range f.identitySources {
+			if s.Name() == src.Name() {
+				f.identitySources[i] = src
+				return
+			}
+		}
+		f.identitySources = append(f.identitySources, src)
+	}
+}
+
+// WithConnectionSource registers src, replacing the built-in connection source of the same name if any
+func WithConnectionSource(src ConnectionSource) Option {
+	return func(f *fetcher) {
+		for i, s := range f.connectionSources {
+			if s.Name() == src.Name() {
+				f.connectionSources[i] = src
+				return
+			}
+		}
+		f.connectionSources = append(f.connectionSources, src)
+	}
+}
+
+// WithDisabledSources is the construction time equivalent of DisableSource
+func WithDisabledSources(names ...string) Option {
+	return func(f *fetcher) {
+		for _, name := range names {
+			f.disabledSources[name] = true
+		}
+	}
+}
+
+// WithEnabledSources is the construction time equivalent of EnableSource, e.g. for sources disabled by default
+func WithEnabledSources(names ...string) Option {
+	return func(f *fetcher) {
+		for _, name := range names {
+			delete(f.disabledSources, name)
+		}
+	}
+}
+
+// WithRequiredSources is the construction time equivalent of RequireSources
+func WithRequiredSources(names ...string) Option {
+	return func(f *fetcher) {
+		for _, name := range names {
+			f.requiredSources[name] = true
+		}
+	}
+}
+
+func (f *fetcher) sourceConfig(source string) *sourceConfig {
+	cfg, ok := f.sourceConfigs[source]
+	if !ok {
+		cfg = &sourceConfig{}
+		f.sourceConfigs[source] = cfg
+	}
+	return cfg
+}
+
+func (f *fetcher) apiKey(source string) string {
+	if cfg, ok := f.sourceConfigs[source]; ok {
+		return cfg.apiKey
+	}
+	return ""
+}
+
+// maxEdges returns the cap of source set with WithMaxEdges, 0 for no cap
+func (f *fetcher) maxEdges(source string) int {
+	cfg, ok := f.sourceConfigs[source]
+	switch {
+	case !ok || cfg.maxEdges == 0:
+		return DefaultMaxEdges
+	case cfg.maxEdges < 0:
+		return 0
+	default:
+		return cfg.maxEdges
+	}
+}
+
+// rebaseURL moves rawURL onto the scheme, host and path prefix of baseURL
+func rebaseURL(rawURL, baseURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+	u.Path = strings.TrimRight(base.Path, "/") + u.Path
+	u.RawPath = ""
+	return u.String(), nil
+}
@@ -0,0 +1,125 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// poapHoldersPageSize is the number of holders asked per page of an event
+const poapHoldersPageSize = 300
+
+// poapHeader sends the key set with WithAPIKey(POAP, key), the POAP API rejects requests without one
+func (f *fetcher) poapHeader() map[string]string {
+	if key := f.apiKey(POAP); key != "" {
+		return map[string]string{"X-API-Key": key}
+	}
+	return nil
+}
+
+// getPoapTokens returns the POAP badges held by address
+func (f *fetcher) getPoapTokens(ctx context.Context, address string) ([]PoapToken, error) {
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: POAP,
+		url:    fmt.Sprintf("%s/actions/scan/%s", PoapUrl, address),
+		method: "GET",
+		header: f.poapHeader(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []PoapToken
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (f *fetcher) processPoap(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	tokens, err := f.getPoapTokens(ctx, address)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processPoap] fetch tokens failed"
+		return result
+	}
+
+	for _, token := range tokens {
+		result.Poap = append(result.Poap, UserPoapIdentity{
+			EventID:    token.Event.ID,
+			TokenID:    token.TokenID,
+			Name:       token.Event.Name,
+			Date:       token.Event.StartDate,
+			ImageURL:   token.Event.ImageURL,
+			Chain:      token.Chain,
+			DataSource: POAP,
+		})
+	}
+	return result
+}
+
+// processPoapCoAttendance links address to every other holder of the events it holds a badge of,
+// one edge per holder however many events they share, requests go out with the options of POAP
+func (f *fetcher) processPoapCoAttendance(ctx context.Context, address string) ConnectionEntryList {
+	result := ConnectionEntryList{}
+
+	tokens, err := f.getPoapTokens(ctx, address)
+	if err != nil {
+		result.Err = err
+		result.msg = "[processPoapCoAttendance] fetch tokens failed"
+		return result
+	}
+
+	maxEdges := f.maxEdges(POAP_COATTENDANCE)
+	seen := map[string]bool{strings.ToLower(address): true}
+	for _, token := range tokens {
+		for offset := 0; ; offset += poapHoldersPageSize {
+			body, err := f.sendRequest(ctx, RequestArgs{
+				source: POAP,
+				url:    fmt.Sprintf("%s/event/%d/poaps", PoapUrl, token.Event.ID),
+				method: "GET",
+				header: f.poapHeader(),
+				params: map[string]string{
+					"limit":  strconv.Itoa(poapHoldersPageSize),
+					"offset": strconv.Itoa(offset),
+				},
+			})
+			if err != nil {
+				result.Err = err
+				result.msg = "[processPoapCoAttendance] fetch event holders failed"
+				return result
+			}
+
+			var holders PoapEventHolders
+			if err := json.Unmarshal(body, &holders); err != nil {
+				result.Err = err
+				result.msg = "[processPoapCoAttendance] event holders response json unmarshal failed"
+				return result
+			}
+			for _, holder := range holders.Tokens {
+				holderAddr := strings.ToLower(holder.Owner.ID)
+				if seen[holderAddr] || !addressFilter(holderAddr) {
+					continue
+				}
+				if maxEdges > 0 && len(result.Conn) >= maxEdges {
+					result.Truncated = true
+					return result
+				}
+				seen[holderAddr] = true
+				result.Conn = append(result.Conn, ConnectionEntry{
+					From:     address,
+					To:       holderAddr,
+					Platform: POAP_COATTENDANCE,
+				})
+			}
+			if len(holders.Tokens) < poapHoldersPageSize || offset+len(holders.Tokens) >= holders.Total {
+				break
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestPoapCoAttendance(t *testing.T) {
+	srv := newTestServer(t)
+
+	// the co-attendance source is opt-in
+	conn, err := newTestFetcher(t, srv).FetchConnectionsWithContext(context.Background(), testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range conn.Status {
+		if s.Source == POAP_COATTENDANCE {
+			t.Errorf("co-attendance queried without being enabled: %+v", s)
+		}
+	}
+
+	f := newTestFetcher(t, srv, WithEnabledSources(POAP_COATTENDANCE), WithAPIKey(POAP, "secret"))
+	conn, err = f.FetchConnectionsWithContext(context.Background(), testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var edges []ConnectionEntry
+	for _, c := range conn.Conn {
+		if c.Platform == POAP_COATTENDANCE {
+			edges = append(edges, c)
+		}
+	}
+	// the holder of both events is linked once, the address itself never
+	want := []ConnectionEntry{
+		{From: testAddress, To: "0xd8da6bf26964af9d7eed9e03e53415d37aa96045", Platform: POAP_COATTENDANCE},
+		{From: testAddress, To: "0x5a384227b65fa093dec03ec34e111db80a040615", Platform: POAP_COATTENDANCE},
+	}
+	if fmt.Sprint(edges) != fmt.Sprint(want) {
+		t.Errorf("co-attendance edges = %+v, want %+v", edges, want)
+	}
+}
+
+func TestPoapCoAttendancePaging(t *testing.T) {
+	srv := newTestServer(t)
+	var gotKey string
+	srv.Handle("/poap/actions/scan/"+testAddress, func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("X-API-Key")
+		fmt.Fprint(w, `[{"event": {"id": 1}}]`)
+	})
+	const holders = 700
+	srv.Handle("/poap/event/1/poaps", func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		fmt.Fprintf(w, `{"total": %d, "tokens": [`, holders)
+		for i := offset; i < holders && i < offset+poapHoldersPageSize; i++ {
+			if i > offset {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"owner": {"id": "0x%040x"}}`, i+1)
+		}
+		fmt.Fprint(w, `]}`)
+	})
+
+	for _, tt := range []struct {
+		maxEdges, want int
+		truncated      bool
+	}{
+		{0, holders, false},
+		{400, 400, true},
+	} {
+		opts := []Option{WithBaseURL(POAP, srv.URL+"/poap"), WithAPIKey(POAP, "secret")}
+		if tt.maxEdges != 0 {
+			opts = append(opts, WithMaxEdges(POAP_COATTENDANCE, tt.maxEdges))
+		}
+		entry := NewFetcher(opts...).processPoapCoAttendance(context.Background(), testAddress)
+		if entry.Err != nil {
+			t.Fatal(entry.Err)
+		}
+		if len(entry.Conn) != tt.want || entry.Truncated != tt.truncated {
+			t.Errorf("cap %d: got %d edges, truncated %v, want %d, %v", tt.maxEdges, len(entry.Conn), entry.Truncated, tt.want, tt.truncated)
+		}
+	}
+	if gotKey != "secret" {
+		t.Errorf("X-API-Key = %q, want secret", gotKey)
+	}
+}
@@ -0,0 +1,97 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// raribleTestPaths are the followings and followers listings under the /rarible base URL
+var raribleTestPaths = []string{"/rarible/marketplace/api/v4/followings", "/rarible/marketplace/api/v4/followers"}
+
+// rariblePager answers the Rarible followings listing with pages over n edges, continuations are edge indexes,
+// and lists no followers, pages hold at most pageCap edges whatever the size asked, 0 for no cap
+func rariblePager(t *testing.T, n, pageCap int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "followers") {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		var req RaribleConnectionReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding Rarible request: %v", err)
+		}
+		offset := 0
+		if req.Continuation != "" {
+			offset, _ = strconv.Atoi(req.Continuation)
+		}
+		size := req.Size
+		if pageCap > 0 && pageCap < size {
+			size = pageCap
+		}
+		var items []string
+		for i := offset; i < n && i < offset+size; i++ {
+			items = append(items, fmt.Sprintf(`{"id": "%d", "following": {"owner": %q, "user": "0x%040x"}}`, i+1, testAddress, i+1))
+		}
+		fmt.Fprintf(w, `[%s]`, strings.Join(items, ","))
+	}
+}
+
+func TestRaribleConnectionsPagination(t *testing.T) {
+	tests := []struct {
+		edges, maxEdges, pageCap int
+		want                     int
+		truncated                bool
+	}{
+		{2500, 0, 0, 2500, false},
+		{2500, 1500, 0, 1500, true},
+		{2000, 2000, 0, 2000, false},
+		{2500, 2000, 0, 2000, true},
+		{DefaultMaxEdges + 10, -1, 0, DefaultMaxEdges + 10, false},
+		// Rarible serving shorter pages than asked
+		{2500, 0, 300, 2500, false},
+		{2500, 1500, 300, 1500, true},
+		{1500, 1500, 300, 1500, false},
+	}
+	for _, tt := range tests {
+		srv := newTestServer(t)
+		for _, path := range raribleTestPaths {
+			srv.Handle(path, rariblePager(t, tt.edges, tt.pageCap))
+		}
+		opts := []Option{WithBaseURL(RARIBLE, srv.URL+"/rarible"), WithRetryPolicy(testRetryPolicy)}
+		if tt.maxEdges != 0 {
+			opts = append(opts, WithMaxEdges(RARIBLE, tt.maxEdges))
+		}
+		f := NewFetcher(opts...)
+
+		entry := f.processRaribleConn(context.Background(), testAddress)
+		if entry.Err != nil {
+			t.Fatal(entry.Err)
+		}
+		if len(entry.Conn) != tt.want || entry.Truncated != tt.truncated {
+			t.Errorf("%d edges with cap %d, pages of %d: got %d edges, truncated %v, want %d, %v",
+				tt.edges, tt.maxEdges, tt.pageCap, len(entry.Conn), entry.Truncated, tt.want, tt.truncated)
+		}
+	}
+}
+
+func TestRaribleConnectionsRequestError(t *testing.T) {
+	srv := newTestServer(t)
+	for _, path := range raribleTestPaths {
+		srv.Handle(path, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+	}
+	f := NewFetcher(WithBaseURL(RARIBLE, srv.URL+"/rarible"))
+
+	entry := f.processRaribleConn(context.Background(), testAddress)
+	var httpErr *HTTPError
+	if !errors.As(entry.Err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Err = %v, want the HTTP 404", entry.Err)
+	}
+}
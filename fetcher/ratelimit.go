@@ -0,0 +1,155 @@
+package fetcher
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimit bounds the requests sent to one upstream host, it is shared by every concurrent
+// FetchIdentity and FetchConnections call of a fetcher and every source talking to that host
+type RateLimit struct {
+	// RequestsPerSecond is the refill rate of the token bucket, 0 means no rate limit
+	RequestsPerSecond float64
+	// Burst is the size of the token bucket, it defaults to 1
+	Burst int
+	// MaxInFlight caps the concurrent requests, 0 means no cap
+	MaxInFlight int
+}
+
+// RateLimitStats is a snapshot of the limiter of one host
+type RateLimitStats struct {
+	Host  string
+	Limit RateLimit
+	// InFlight and Waiting are the requests currently sent and queued
+	InFlight int
+	Waiting  int
+	// Requests is the number of requests let through so far
+	Requests int64
+	// WaitTime is the total time requests spent queued so far
+	WaitTime time.Duration
+}
+
+// WithRateLimit limits the requests sent to host, e.g. "api.opensea.io"
+// the host is matched after WithBaseURL is applied, so mirrors are limited separately
+func WithRateLimit(host string, limit RateLimit) Option {
+	return func(f *fetcher) {
+		f.limiters[host] = newHostLimiter(host, limit)
+	}
+}
+
+type hostLimiter struct {
+	host   string
+	limit  RateLimit
+	bucket *rate.Limiter
+	slots  chan struct{}
+
+	mu       sync.Mutex
+	inFlight int
+	waiting  int
+	requests int64
+	waitTime time.Duration
+}
+
+func newHostLimiter(host string, limit RateLimit) *hostLimiter {
+	l := &hostLimiter{host: host, limit: limit}
+	if limit.RequestsPerSecond > 0 {
+		burst := limit.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		l.bucket = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+	}
+	if limit.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+// acquire blocks until the request may be sent or ctx is done, the returned release must be called
+// once the response has been read
+func (l *hostLimiter) acquire(ctx context.Context) (release func(), err error) {
+	start := time.Now()
+	l.mu.Lock()
+	l.waiting++
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.waiting--
+		l.waitTime += time.Since(start)
+		if err == nil {
+			l.inFlight++
+			l.requests++
+		}
+		l.mu.Unlock()
+	}()
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if l.bucket != nil {
+		if err := l.bucket.Wait(ctx); err != nil {
+			if l.slots != nil {
+				<-l.slots
+			}
+			// rate.Limiter reports a wait that would outlast the deadline with its own error
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, context.DeadlineExceeded
+		}
+	}
+
+	return func() {
+		l.mu.Lock()
+		l.inFlight--
+		l.mu.Unlock()
+		if l.slots != nil {
+			<-l.slots
+		}
+	}, nil
+}
+
+func (l *hostLimiter) stats() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return RateLimitStats{
+		Host:     l.host,
+		Limit:    l.limit,
+		InFlight: l.inFlight,
+		Waiting:  l.waiting,
+		Requests: l.requests,
+		WaitTime: l.waitTime,
+	}
+}
+
+// acquireHost waits for the limiter of the host of rawURL, hosts without a limit pass through
+func (f *fetcher) acquireHost(ctx context.Context, rawURL string) (release func(), err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	l, ok := f.limiters[u.Hostname()]
+	if !ok {
+		return func() {}, nil
+	}
+	return l.acquire(ctx)
+}
+
+// RateLimitStats reports the state of every host limiter configured with WithRateLimit, sorted by host
+func (f *fetcher) RateLimitStats() []RateLimitStats {
+	var stats []RateLimitStats
+	for _, l := range f.limiters {
+		stats = append(stats, l.stats())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Host < stats[j].Host })
+	return stats
+}
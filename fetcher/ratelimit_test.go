@@ -0,0 +1,105 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitMaxInFlight(t *testing.T) {
+	srv := newTestServer(t)
+	var current, peak int32
+	release := make(chan struct{})
+	srv.Handle("/slow", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&current, -1)
+		w.Write([]byte(`{}`))
+	})
+	f := newTestFetcher(t, srv, WithRateLimit("127.0.0.1", RateLimit{MaxInFlight: 2}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.sendRequest(context.Background(), RequestArgs{source: CONTEXT, url: srv.URL + "/slow", method: "GET"})
+		}()
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stats := f.RateLimitStats()
+		if len(stats) == 1 && stats[0].InFlight == 2 && stats[0].Waiting == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("stats = %+v, want 2 in flight and 3 waiting", stats)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	close(release)
+	wg.Wait()
+	if peak != 2 {
+		t.Errorf("peak concurrency %d, want 2", peak)
+	}
+	if stats := f.RateLimitStats()[0]; stats.Requests != 5 || stats.InFlight != 0 || stats.Waiting != 0 {
+		t.Errorf("stats = %+v after all requests finished", stats)
+	}
+}
+
+func TestRateLimitRequestsPerSecond(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Handle("/fast", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	f := newTestFetcher(t, srv, WithRateLimit("127.0.0.1", RateLimit{RequestsPerSecond: 20, Burst: 1}))
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := f.sendRequest(context.Background(), RequestArgs{source: CONTEXT, url: srv.URL + "/fast", method: "GET"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("5 requests at 20/s took %v", elapsed)
+	}
+}
+
+func TestRateLimitContextCancel(t *testing.T) {
+	srv := newTestServer(t)
+	release := make(chan struct{})
+	srv.Handle("/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	f := newTestFetcher(t, srv, WithRateLimit("127.0.0.1", RateLimit{MaxInFlight: 1}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.sendRequest(context.Background(), RequestArgs{source: CONTEXT, url: srv.URL + "/slow", method: "GET"})
+	}()
+	for f.RateLimitStats()[0].InFlight != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := f.sendRequest(ctx, RequestArgs{source: CONTEXT, url: srv.URL + "/slow", method: "GET"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded while queued", err)
+	}
+	close(release)
+	<-done
+}
@@ -0,0 +1,156 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+// ErrNoNameResolver is returned for a name given as input when no Ethereum backend is configured
+var ErrNoNameResolver = errors.New("no Ethereum backend configured to resolve names, see WithEthRPC")
+
+// InvalidInputError is returned when the input is neither an Ethereum address nor a name
+type InvalidInputError struct {
+	Input string
+}
+
+func (e *InvalidInputError) Error() string {
+	return fmt.Sprintf("invalid input %q: expected an Ethereum address or an ENS name", e.Input)
+}
+
+// ResolveError is returned when a name given as input could not be resolved to an address
+type ResolveError struct {
+	Name string
+	Err  error
+}
+
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf("resolving %s: %v", e.Name, e.Err)
+}
+
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+// NameResolver resolves names such as brantly.eth to Ethereum addresses
+type NameResolver interface {
+	Resolve(ctx context.Context, name string) (address string, err error)
+}
+
+// WithNameResolver resolves names given as input with r, e.g. a local stand-in in tests
+func WithNameResolver(r NameResolver) Option {
+	return func(f *fetcher) {
+		f.nameResolver = r
+	}
+}
+
+// WithEthBackend resolves ENS names through backend, e.g. an *ethclient.Client
+func WithEthBackend(backend bind.ContractBackend) Option {
+	return func(f *fetcher) {
+		f.nameResolver = &ensResolver{backend: func() (bind.ContractBackend, error) { return backend, nil }}
+	}
+}
+
+// WithEthRPC resolves ENS names through the Ethereum JSON-RPC endpoint at rpcURL, e.g. an Infura project URL
+// the endpoint is dialed on first use
+func WithEthRPC(rpcURL string) Option {
+	return func(f *fetcher) {
+		var once sync.Once
+		var client *ethclient.Client
+		var dialErr error
+		f.nameResolver = &ensResolver{backend: func() (bind.ContractBackend, error) {
+			once.Do(func() {
+				client, dialErr = ethclient.Dial(rpcURL)
+			})
+			return client, dialErr
+		}}
+	}
+}
+
+// ensResolver is the NameResolver backed by the ENS contracts
+type ensResolver struct {
+	backend func() (bind.ContractBackend, error)
+}
+
+func (r *ensResolver) Resolve(ctx context.Context, name string) (string, error) {
+	var address common.Address
+	err := r.run(ctx, func(backend bind.ContractBackend) (err error) {
+		address, err = ens.Resolve(backend, name)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(address.Hex()), nil
+}
+
+// run calls fn with the backend, go-ens does not take a context so on cancellation the call is abandoned
+// rather than aborted
+func (r *ensResolver) run(ctx context.Context, fn func(backend bind.ContractBackend) error) error {
+	backend, err := r.backend()
+	if err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(backend)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// resolveInput turns the address or name given by the caller into the lowercase hex address queried from
+// every source, name is set when the input was a name
+func (f *fetcher) resolveInput(ctx context.Context, input string) (address, name string, err error) {
+	input = strings.TrimSpace(input)
+	if isAddress(input) {
+		return "0x" + strings.TrimPrefix(strings.ToLower(input), "0x"), "", nil
+	}
+
+	name, ok := normaliseName(input)
+	if !ok {
+		return "", "", &InvalidInputError{Input: input}
+	}
+	if f.nameResolver == nil {
+		return "", name, &ResolveError{Name: name, Err: ErrNoNameResolver}
+	}
+	address, err = f.nameResolver.Resolve(ctx, name)
+	if err != nil {
+		return "", name, &ResolveError{Name: name, Err: err}
+	}
+	if !isAddress(address) || common.HexToAddress(address) == (common.Address{}) {
+		return "", name, &ResolveError{Name: name, Err: errors.New("name does not resolve to an address")}
+	}
+	return strings.ToLower(address), name, nil
+}
+
+// normaliseName returns the ENS normal form of input, unicode and emoji labels included, ok is false unless
+// input is a name of at least two non-empty labels without spaces or control characters
+func normaliseName(input string) (name string, ok bool) {
+	name, err := ens.NormaliseDomain(input)
+	if err != nil || strings.HasPrefix(name, "*.") {
+		return "", false
+	}
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return "", false
+	}
+	for _, label := range labels {
+		if label == "" || strings.IndexFunc(label, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+			return "", false
+		}
+	}
+	return name, true
+}
@@ -0,0 +1,148 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// stubResolver stands in for the ENS contracts
+type stubResolver map[string]string
+
+func (r stubResolver) Resolve(ctx context.Context, name string) (string, error) {
+	address, ok := r[name]
+	if !ok {
+		return "", errors.New("unregistered name")
+	}
+	return address, nil
+}
+
+func TestResolveInput(t *testing.T) {
+	f := NewFetcher(WithNameResolver(stubResolver{
+		"brantly.eth": "0x983110309620D911731Ac0932219af06091b6744",
+		"zero.eth":    "0x0000000000000000000000000000000000000000",
+		"über.eth":    "0x983110309620D911731Ac0932219af06091b6744",
+		"🦊.eth":       "0x983110309620D911731Ac0932219af06091b6744",
+	}))
+	tests := []struct {
+		input, address, name string
+		err                  interface{}
+	}{
+		{"0x983110309620D911731Ac0932219af06091b6744", testAddress, "", nil},
+		{"983110309620d911731ac0932219af06091b6744", testAddress, "", nil},
+		{" Brantly.eth ", testAddress, "brantly.eth", nil},
+		{"Über.eth", testAddress, "über.eth", nil},
+		{"🦊.eth", testAddress, "🦊.eth", nil},
+		{"xn--ls8h.eth", "", "💩.eth", new(*ResolveError)},
+		{"nobody.eth", "", "nobody.eth", new(*ResolveError)},
+		{"zero.eth", "", "zero.eth", new(*ResolveError)},
+		{"0x9831", "", "", new(*InvalidInputError)},
+		{"not a name", "", "", new(*InvalidInputError)},
+		{"not a.eth", "", "", new(*InvalidInputError)},
+		{"brantly..eth", "", "", new(*InvalidInputError)},
+		{"*.brantly.eth", "", "", new(*InvalidInputError)},
+		{"", "", "", new(*InvalidInputError)},
+	}
+	for _, tt := range tests {
+		address, name, err := f.resolveInput(context.Background(), tt.input)
+		if address != tt.address || name != tt.name {
+			t.Errorf("resolveInput(%q) = %q, %q, want %q, %q", tt.input, address, name, tt.address, tt.name)
+		}
+		if tt.err == nil && err != nil {
+			t.Errorf("resolveInput(%q) failed: %v", tt.input, err)
+		}
+		if tt.err != nil && !errors.As(err, tt.err) {
+			t.Errorf("resolveInput(%q) error = %v, want %T", tt.input, err, tt.err)
+		}
+	}
+
+	_, _, err := NewFetcher().resolveInput(context.Background(), "brantly.eth")
+	if !errors.Is(err, ErrNoNameResolver) {
+		t.Errorf("resolving without a backend: %v, want ErrNoNameResolver", err)
+	}
+}
+
+func TestFetchByName(t *testing.T) {
+	srv := newTestServer(t)
+	f := newTestFetcher(t, srv, WithNameResolver(stubResolver{"brantly.eth": testAddress}))
+
+	ids, err := f.FetchIdentityWithContext(context.Background(), "brantly.eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids.Address != testAddress || ids.Name != "brantly.eth" || len(ids.Superrare) == 0 {
+		t.Errorf("FetchIdentity(brantly.eth) = %+v", ids)
+	}
+
+	conn, err := f.FetchConnectionsWithContext(context.Background(), "brantly.eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn.Address != testAddress || conn.Name != "brantly.eth" || len(conn.Conn) == 0 {
+		t.Errorf("FetchConnections(brantly.eth) = %+v", conn)
+	}
+
+	// invalid input never reaches the upstream APIs
+	ids, err = f.FetchIdentity("brantly")
+	var inputErr *InvalidInputError
+	if !errors.As(err, &inputErr) || len(ids.Status) != 0 {
+		t.Errorf("FetchIdentity(brantly) = %+v, %v, want an *InvalidInputError", ids, err)
+	}
+}
+
+func TestEnsResolverContracts(t *testing.T) {
+	srv := brantlyChain(t).serve(t)
+	defer srv.Close()
+	f := NewFetcher(WithEthRPC(srv.URL))
+
+	tests := []struct {
+		input, address string
+		err            bool
+	}{
+		{"brantly.eth", testAddress, false},
+		// registered without an address
+		{"zero.eth", "", true},
+		// no owner in the registry
+		{"nobody.eth", "", true},
+	}
+	for _, tt := range tests {
+		address, _, err := f.resolveInput(context.Background(), tt.input)
+		var resolveErr *ResolveError
+		if address != tt.address || tt.err != errors.As(err, &resolveErr) {
+			t.Errorf("resolveInput(%q) = %q, %v, want %q, error %v", tt.input, address, err, tt.address, tt.err)
+		}
+	}
+}
+
+func TestEnsResolverBackendErrors(t *testing.T) {
+	// the endpoint is gone by the time the name is resolved
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	_, _, err := NewFetcher(WithEthRPC(srv.URL)).resolveInput(context.Background(), "brantly.eth")
+	var resolveErr *ResolveError
+	if !errors.As(err, &resolveErr) {
+		t.Errorf("resolving through a closed endpoint: %v, want *ResolveError", err)
+	}
+
+	_, _, err = NewFetcher(WithEthRPC("unknown://endpoint")).resolveInput(context.Background(), "brantly.eth")
+	if !errors.As(err, &resolveErr) {
+		t.Errorf("resolving through an endpoint that cannot be dialed: %v, want *ResolveError", err)
+	}
+
+	// go-ens takes no context, a stalled endpoint is abandoned once the caller gives up
+	release := make(chan struct{})
+	stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer stalled.Close()
+	defer close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err = NewFetcher(WithEthRPC(stalled.URL)).resolveInput(ctx, "brantly.eth")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("resolving through a stalled endpoint: %v, want the deadline", err)
+	}
+}
@@ -0,0 +1,126 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RetryPolicy controls how failed upstream requests are retried
+// GET requests are always eligible, POST requests only when the source marks them idempotent
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one, 1 or less disables retries
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry, it doubles with every further retry
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff, a Retry-After asking for a longer wait ends the retries
+	MaxDelay time.Duration
+	// RetryableStatus is the set of HTTP status codes worth retrying
+	RetryableStatus map[int]bool
+}
+
+// DefaultRetryPolicy retries rate limited and 5xx gateway responses twice
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   250 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		RetryableStatus: map[int]bool{
+			http.StatusTooManyRequests:     true,
+			http.StatusInternalServerError: true,
+			http.StatusBadGateway:          true,
+			http.StatusServiceUnavailable:  true,
+			http.StatusGatewayTimeout:      true,
+		},
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy for every source
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(f *fetcher) {
+		f.retryPolicy = policy
+	}
+}
+
+// retryDelay returns how long to wait before the given retry (1 for the first retry) after err,
+// ok is false when err must not be retried
+func (p RetryPolicy) retryDelay(retry int, err error) (delay time.Duration, ok bool) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && !p.RetryableStatus[httpErr.StatusCode] {
+		return 0, false
+	}
+
+	// exponential backoff with equal jitter
+	backoff := p.BaseDelay << uint(retry-1)
+	if backoff > p.MaxDelay || backoff <= 0 {
+		backoff = p.MaxDelay
+	}
+	delay = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+
+	if httpErr != nil && httpErr.RetryAfter > 0 {
+		if httpErr.RetryAfter > p.MaxDelay {
+			return 0, false
+		}
+		if httpErr.RetryAfter > delay {
+			delay = httpErr.RetryAfter
+		}
+	}
+	return delay, true
+}
+
+// sendWithRetry retries sendRequest according to the fetcher's retry policy
+func (f *fetcher) sendWithRetry(ctx context.Context, args RequestArgs) ([]byte, error) {
+	idempotent := args.method == "GET" || args.idempotent
+	for attempt := 1; ; attempt++ {
+		release, err := f.acquireHost(ctx, args.url)
+		if err != nil {
+			return nil, err
+		}
+		body, err := sendRequest(ctx, f.httpClient, args)
+		release()
+		if err == nil || !idempotent || attempt >= f.retryPolicy.MaxAttempts {
+			return body, err
+		}
+		delay, ok := f.retryPolicy.retryDelay(attempt, err)
+		if !ok {
+			return body, err
+		}
+
+		zap.L().With(zap.Error(err), zap.String("source", args.source), zap.Int("attempt", attempt), zap.Duration("delay", delay)).
+			Debug("retrying upstream request")
+		recordRetry(ctx)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// parseRetryAfter understands both forms of the Retry-After header, delay-seconds and HTTP-date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransientFailure(t *testing.T) {
+	srv := newTestServer(t)
+	var calls int32
+	srv.Handle("/api/v2/user", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"result": {"username": "brantly"}}`))
+	})
+	f := newTestFetcher(t, srv)
+
+	ids, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spr := statusOf(t, ids.Status, SUPERRARE)
+	if spr.State != SourceSuccess || spr.Retries != 2 || spr.HTTPStatus != http.StatusOK {
+		t.Errorf("Superrare status = %+v, want success after 2 retries", spr)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	srv := newTestServer(t)
+	var calls int32
+	srv.Handle("/api/v2/user", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	f := newTestFetcher(t, srv)
+
+	ids, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spr := statusOf(t, ids.Status, SUPERRARE); spr.Retries != 0 || calls != 1 {
+		t.Errorf("404 was retried: status %+v after %d calls", spr, calls)
+	}
+}
+
+func TestRetryNonIdempotentPost(t *testing.T) {
+	srv := newTestServer(t)
+	var calls int32
+	srv.Handle("/post", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	f := newTestFetcher(t, srv)
+
+	args := RequestArgs{source: CONTEXT, url: srv.URL + "/post", method: "POST"}
+	if _, err := f.sendRequest(context.Background(), args); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Errorf("non-idempotent POST sent %d times", calls)
+	}
+
+	calls = 0
+	args.idempotent = true
+	f.sendRequest(context.Background(), args)
+	if calls != int32(testRetryPolicy.MaxAttempts) {
+		t.Errorf("idempotent POST sent %d times, want %d", calls, testRetryPolicy.MaxAttempts)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	srv := newTestServer(t)
+	var calls int32
+	srv.Handle("/limited", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+	policy := testRetryPolicy
+	policy.MaxDelay = 2 * time.Second
+	f := newTestFetcher(t, srv, WithRetryPolicy(policy))
+
+	start := time.Now()
+	if _, err := f.sendRequest(context.Background(), RequestArgs{source: CONTEXT, url: srv.URL + "/limited", method: "GET"}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", elapsed)
+	}
+
+	// a Retry-After beyond MaxDelay is not waited for
+	calls = 0
+	f = newTestFetcher(t, srv)
+	_, err := f.sendRequest(context.Background(), RequestArgs{source: CONTEXT, url: srv.URL + "/limited", method: "GET"})
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.RetryAfter != time.Second || calls != 1 {
+		t.Errorf("err = %v after %d calls, want the 429 without retry", err, calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := parseRetryAfter("3"); d != 3*time.Second {
+		t.Errorf("parseRetryAfter(3) = %v", d)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if d := parseRetryAfter(date); d < 58*time.Second || d > time.Minute {
+		t.Errorf("parseRetryAfter(%s) = %v", date, d)
+	}
+	if d := parseRetryAfter("soon"); d != 0 {
+		t.Errorf("parseRetryAfter(soon) = %v", d)
+	}
+}
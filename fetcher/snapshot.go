@@ -0,0 +1,167 @@
+package fetcher
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// snapshotPageSize is the largest page the Snapshot hub serves
+const snapshotPageSize = 1000
+
+// snapshotNoCursor starts the listings, created is a Unix time, the hub rejects skip past 5000 so pages
+// follow each other on created_lte instead, rows sharing the timestamp of the page boundary are served
+// again on the next page and dropped by id
+const snapshotNoCursor = math.MaxInt32
+
+const snapshotFollowsQuery = `query Follows($follower: String!, $before: Int!, $first: Int!) {
+	follows(where: {follower: $follower, created_lte: $before}, first: $first, orderBy: "created", orderDirection: desc) {
+		id
+		space { id name }
+		created
+	}
+}`
+
+const snapshotVotesQuery = `query Votes($voter: String!, $before: Int!, $first: Int!) {
+	votes(where: {voter: $voter, created_lte: $before}, first: $first, orderBy: "created", orderDirection: desc) {
+		id
+		space { id }
+		proposal { id title }
+		choice
+		vp
+		created
+	}
+}`
+
+// getSnapshotSpaces pages through the spaces followed by address, up to maxEdges spaces, 0 for no cap
+func (f *fetcher) getSnapshotSpaces(ctx context.Context, address string, maxEdges int) (spaces []SnapshotSpace, truncated bool, err error) {
+	// the hub matches voters and followers on the checksummed address
+	follower := common.HexToAddress(address).Hex()
+	seen := make(map[string]bool)
+	var before int64 = snapshotNoCursor
+	for {
+		var page SnapshotFollows
+		err := f.graphQLQuery(ctx, SNAPSHOT, SnapshotUrl, snapshotFollowsQuery,
+			map[string]interface{}{"follower": follower, "before": before, "first": snapshotPageSize}, &page)
+		if err != nil {
+			return nil, false, err
+		}
+		added := 0
+		for _, follow := range page.Follows {
+			if seen[follow.ID] {
+				continue
+			}
+			if maxEdges > 0 && len(spaces) >= maxEdges {
+				return spaces, true, nil
+			}
+			seen[follow.ID] = true
+			added++
+			spaces = append(spaces, SnapshotSpace{
+				ID:         follow.Space.ID,
+				Name:       follow.Space.Name,
+				FollowedAt: time.Unix(follow.Created, 0).UTC(),
+			})
+		}
+		// a full page of rows already seen all share one timestamp, the listing cannot move past it
+		if len(page.Follows) < snapshotPageSize || added == 0 {
+			return spaces, false, nil
+		}
+		before = page.Follows[len(page.Follows)-1].Created
+	}
+}
+
+// getSnapshotVotes pages through the votes of address, up to maxEdges votes, 0 for no cap
+func (f *fetcher) getSnapshotVotes(ctx context.Context, address string, maxEdges int) (votes []SnapshotVote, truncated bool, err error) {
+	voter := common.HexToAddress(address).Hex()
+	seen := make(map[string]bool)
+	var before int64 = snapshotNoCursor
+	for {
+		var page SnapshotVotes
+		err := f.graphQLQuery(ctx, SNAPSHOT, SnapshotUrl, snapshotVotesQuery,
+			map[string]interface{}{"voter": voter, "before": before, "first": snapshotPageSize}, &page)
+		if err != nil {
+			return nil, false, err
+		}
+		added := 0
+		for _, vote := range page.Votes {
+			if seen[vote.ID] {
+				continue
+			}
+			if maxEdges > 0 && len(votes) >= maxEdges {
+				return votes, true, nil
+			}
+			seen[vote.ID] = true
+			added++
+			votes = append(votes, SnapshotVote{
+				Space:         vote.Space.ID,
+				ProposalID:    vote.Proposal.ID,
+				ProposalTitle: vote.Proposal.Title,
+				Choice:        string(vote.Choice),
+				VotingPower:   vote.Vp,
+				VotedAt:       time.Unix(vote.Created, 0).UTC(),
+			})
+		}
+		if len(page.Votes) < snapshotPageSize || added == 0 {
+			return votes, false, nil
+		}
+		before = page.Votes[len(page.Votes)-1].Created
+	}
+}
+
+// processSnapshot lists the spaces address follows and the proposals it voted on, newest first, each up to
+// the WithMaxEdges cap of SNAPSHOT
+func (f *fetcher) processSnapshot(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	spaces, _, err := f.getSnapshotSpaces(ctx, address, f.maxEdges(SNAPSHOT))
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processSnapshot] fetch follows failed"
+		return result
+	}
+
+	votes, _, err := f.getSnapshotVotes(ctx, address, f.maxEdges(SNAPSHOT))
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processSnapshot] fetch votes failed"
+		return result
+	}
+
+	if len(spaces) != 0 || len(votes) != 0 {
+		result.Snapshot = &UserSnapshotIdentity{
+			Spaces:     spaces,
+			Votes:      votes,
+			DataSource: SNAPSHOT,
+		}
+	}
+	return result
+}
+
+// processSnapshotConn links address to the spaces it follows, requests go out with the options of SNAPSHOT
+func (f *fetcher) processSnapshotConn(ctx context.Context, address string) ConnectionEntryList {
+	result := ConnectionEntryList{}
+
+	spaces, truncated, err := f.getSnapshotSpaces(ctx, address, f.maxEdges(SNAPSHOT_FOLLOWS))
+	if err != nil {
+		result.Err = err
+		result.msg = "[processSnapshotConn] fetch follows failed"
+		return result
+	}
+	result.Truncated = truncated
+
+	for _, space := range spaces {
+		to := strings.ToLower(space.ID)
+		if !addressFilter(to) {
+			continue
+		}
+		result.Conn = append(result.Conn, ConnectionEntry{
+			From:     address,
+			To:       to,
+			Platform: SNAPSHOT,
+		})
+	}
+	return result
+}
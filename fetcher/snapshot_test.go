@@ -0,0 +1,92 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotFollowsConnections(t *testing.T) {
+	srv := newTestServer(t)
+	f := newTestFetcher(t, srv, WithEnabledSources(SNAPSHOT_FOLLOWS))
+
+	conn, err := f.FetchConnectionsWithContext(context.Background(), testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var edges []ConnectionEntry
+	for _, c := range conn.Conn {
+		if c.Platform == SNAPSHOT {
+			edges = append(edges, c)
+		}
+	}
+	// spaces not named after an ENS name are left out like any other endpoint failing addressFilter
+	want := []ConnectionEntry{{From: testAddress, To: "ens.eth", Platform: SNAPSHOT}}
+	if fmt.Sprint(edges) != fmt.Sprint(want) {
+		t.Errorf("Snapshot edges = %+v, want %+v", edges, want)
+	}
+}
+
+func TestSnapshotPaging(t *testing.T) {
+	srv := newTestServer(t)
+	// more votes than skip could reach, the hub rejects skip past 5000
+	const follows, votes = 2500, 6500
+	srv.Handle("/snapshot/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if _, ok := req.Variables["skip"]; ok {
+			t.Error("paging with skip")
+		}
+		// item k of n, newest first, was created at (k+2)/3, three items share every timestamp so
+		// page boundaries fall between items created at the same time
+		before := int(req.Variables["before"].(float64))
+		page := func(n int, item string) string {
+			var items []string
+			for k := n; k > 0 && len(items) < snapshotPageSize; k-- {
+				if created := (k + 2) / 3; created <= before {
+					items = append(items, fmt.Sprintf(item, k, k, created))
+				}
+			}
+			return strings.Join(items, ",")
+		}
+		if strings.Contains(req.Query, "follows") {
+			fmt.Fprintf(w, `{"data": {"follows": [%s]}}`, page(follows, `{"id": "f%d", "space": {"id": "space%d.eth"}, "created": %d}`))
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"votes": [%s]}}`, page(votes, `{"id": "v%d", "proposal": {"id": "p%d"}, "choice": 1, "created": %d}`))
+	})
+
+	f := NewFetcher(WithBaseURL(SNAPSHOT, srv.URL+"/snapshot"), WithMaxEdges(SNAPSHOT, -1), WithMaxEdges(SNAPSHOT_FOLLOWS, 1500))
+	entry := f.processSnapshot(context.Background(), testAddress)
+	if entry.Err != nil || entry.Snapshot == nil || len(entry.Snapshot.Spaces) != follows || len(entry.Snapshot.Votes) != votes {
+		t.Fatalf("identity = %v, want %d spaces and %d votes", entry.Err, follows, votes)
+	}
+	proposals := make(map[string]bool)
+	for _, vote := range entry.Snapshot.Votes {
+		proposals[vote.ProposalID] = true
+	}
+	if len(proposals) != votes {
+		t.Errorf("%d distinct votes, want %d without duplicates", len(proposals), votes)
+	}
+	if last := entry.Snapshot.Votes[votes-1]; last.ProposalID != "p1" {
+		t.Errorf("last vote = %+v, want the oldest one", last)
+	}
+
+	conn := f.processSnapshotConn(context.Background(), testAddress)
+	if conn.Err != nil || len(conn.Conn) != 1500 || !conn.Truncated {
+		t.Errorf("connections: %d edges, truncated %v, err %v, want 1500 truncated", len(conn.Conn), conn.Truncated, conn.Err)
+	}
+
+	// the identity source stops both listings at the cap of SNAPSHOT
+	f = NewFetcher(WithBaseURL(SNAPSHOT, srv.URL+"/snapshot"), WithMaxEdges(SNAPSHOT, 100))
+	entry = f.processSnapshot(context.Background(), testAddress)
+	if entry.Err != nil || len(entry.Snapshot.Spaces) != 100 || len(entry.Snapshot.Votes) != 100 {
+		t.Errorf("identity with cap = %v, %d spaces and %d votes, want 100 of each", entry.Err, len(entry.Snapshot.Spaces), len(entry.Snapshot.Votes))
+	}
+}
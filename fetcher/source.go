@@ -0,0 +1,209 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+)
+
+// IdentitySource is a data source that contributes identity data for an address
+// failures are reported through IdentityEntry.Err rather than a separate error value
+type IdentitySource interface {
+	// Name uniquely identifies the source within the registry, e.g. SUPERRARE
+	Name() string
+	FetchIdentity(ctx context.Context, address string) IdentityEntry
+}
+
+// ConnectionSource is a data source that contributes following / follower connections for an address
+// failures are reported through ConnectionEntryList.Err rather than a separate error value
+type ConnectionSource interface {
+	// Name uniquely identifies the source within the registry, e.g. RARIBLE
+	Name() string
+	FetchConnections(ctx context.Context, address string) ConnectionEntryList
+}
+
+type identitySourceFunc struct {
+	name string
+	fn   func(ctx context.Context, address string) IdentityEntry
+}
+
+func (s identitySourceFunc) Name() string { return s.name }
+
+func (s identitySourceFunc) FetchIdentity(ctx context.Context, address string) IdentityEntry {
+	return s.fn(ctx, address)
+}
+
+type connectionSourceFunc struct {
+	name string
+	fn   func(ctx context.Context, address string) ConnectionEntryList
+}
+
+func (s connectionSourceFunc) Name() string { return s.name }
+
+func (s connectionSourceFunc) FetchConnections(ctx context.Context, address string) ConnectionEntryList {
+	return s.fn(ctx, address)
+}
+
+// NewIdentitySource adapts a plain function into an IdentitySource
+func NewIdentitySource(name string, fn func(ctx context.Context, address string) IdentityEntry) IdentitySource {
+	return identitySourceFunc{name: name, fn: fn}
+}
+
+// NewConnectionSource adapts a plain function into a ConnectionSource
+func NewConnectionSource(name string, fn func(ctx context.Context, address string) ConnectionEntryList) ConnectionSource {
+	return connectionSourceFunc{name: name, fn: fn}
+}
+
+func (f *fetcher) registerDefaultSources() {
+	// Part 1 - Demo data source
+	f.identitySources = append(f.identitySources,
+		NewIdentitySource(CONTEXT, f.processContext),
+		NewIdentitySource(SUPERRARE, f.processSuperrare),
+	)
+	f.connectionSources = append(f.connectionSources,
+		NewConnectionSource(CONTEXT, f.processContextConn),
+		NewConnectionSource(RARIBLE, f.processRaribleConn),
+		NewConnectionSource(CONVO, f.processConvoConn),
+		NewConnectionSource(LENS, f.processLensConn),
+		NewConnectionSource(FARCASTER, f.processFarcasterConn),
+		NewConnectionSource(POAP_COATTENDANCE, f.processPoapCoAttendance),
+		NewConnectionSource(SNAPSHOT_FOLLOWS, f.processSnapshotConn),
+	)
+	// co-attendance fans out to every holder of every event, it is opt-in with WithEnabledSources
+	f.disabledSources[POAP_COATTENDANCE] = true
+	// space follows link an address to a space rather than to another account, they are opt-in as well
+	f.disabledSources[SNAPSHOT_FOLLOWS] = true
+
+	// Part 2 - Other data source
+	f.identitySources = append(f.identitySources,
+		NewIdentitySource(FOUNDATION, f.processFoundationNonSocial),
+		NewIdentitySource(FOUNDATION_SOCIAL, f.processFoundation),
+		NewIdentitySource(OPENSEA, f.processOpenSea),
+		NewIdentitySource(ZORA, f.processZora),
+		NewIdentitySource(RARIBLE, f.processRarible),
+		NewIdentitySource(SHOWTIME, f.processShowtime),
+		NewIdentitySource(SYBIL, f.processSybil),
+		NewIdentitySource(CONVO, f.processConvo),
+		NewIdentitySource(LENS, f.processLens),
+		NewIdentitySource(FARCASTER, f.processFarcaster),
+		NewIdentitySource(POAP, f.processPoap),
+		NewIdentitySource(MIRROR, f.processMirror),
+		NewIdentitySource(SNAPSHOT, f.processSnapshot),
+		NewIdentitySource(TEZOS, f.processTezos),
+		NewIdentitySource(GITCOIN, f.processGitcoin),
+	)
+}
+
+// registerEthSources adds the sources reading on-chain data once the options have configured an Ethereum backend,
+// a source given under the same name with WithIdentitySource takes their place
+func (f *fetcher) registerEthSources() {
+	if _, ok := f.nameResolver.(ENSReader); ok && !f.hasIdentitySource(ENS) {
+		f.identitySources = append(f.identitySources, NewIdentitySource(ENS, f.processEns))
+	}
+}
+
+// registerKeyedSources adds the sources whose API rejects requests without a key, once the options have set one,
+// a source given under the same name with WithIdentitySource takes their place
+func (f *fetcher) registerKeyedSources() {
+	if f.apiKey(UNSTOPPABLE) != "" && !f.hasIdentitySource(UNSTOPPABLE) {
+		f.identitySources = append(f.identitySources, NewIdentitySource(UNSTOPPABLE, f.processUnstoppable))
+	}
+}
+
+// hasIdentitySource reports whether an identity source is registered under name, disabled or not
+func (f *fetcher) hasIdentitySource(name string) bool {
+	f.sourceMu.RLock()
+	defer f.sourceMu.RUnlock()
+	for _, s := range f.identitySources {
+		if s.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterIdentitySource adds src to the identity sources queried by FetchIdentity
+// it must be called before the fetcher is used, registering a name twice is an error
+func (f *fetcher) RegisterIdentitySource(src IdentitySource) error {
+	f.sourceMu.Lock()
+	defer f.sourceMu.Unlock()
+
+	for _, s := range f.identitySources {
+		if s.Name() == src.Name() {
+			return fmt.Errorf("identity source %s already registered", src.Name())
+		}
+	}
+	f.identitySources = append(f.identitySources, src)
+	return nil
+}
+
+// RegisterConnectionSource adds src to the connection sources queried by FetchConnections
+// it must be called before the fetcher is used, registering a name twice is an error
+func (f *fetcher) RegisterConnectionSource(src ConnectionSource) error {
+	f.sourceMu.Lock()
+	defer f.sourceMu.Unlock()
+
+	for _, s := range f.connectionSources {
+		if s.Name() == src.Name() {
+			return fmt.Errorf("connection source %s already registered", src.Name())
+		}
+	}
+	f.connectionSources = append(f.connectionSources, src)
+	return nil
+}
+
+// DisableSource stops both the identity and the connection source registered under name from being queried
+func (f *fetcher) DisableSource(name string) {
+	f.sourceMu.Lock()
+	defer f.sourceMu.Unlock()
+	f.disabledSources[name] = true
+}
+
+// EnableSource reverts a previous DisableSource
+func (f *fetcher) EnableSource(name string) {
+	f.sourceMu.Lock()
+	defer f.sourceMu.Unlock()
+	delete(f.disabledSources, name)
+}
+
+// isSourceDisabled reports whether name was turned off with WithDisabledSources or DisableSource
+func (f *fetcher) isSourceDisabled(name string) bool {
+	f.sourceMu.RLock()
+	defer f.sourceMu.RUnlock()
+	return f.disabledSources[name]
+}
+
+func (f *fetcher) enabledIdentitySources() []IdentitySource {
+	f.sourceMu.RLock()
+	defer f.sourceMu.RUnlock()
+
+	var sources []IdentitySource
+	for _, s := range f.identitySources {
+		if !f.disabledSources[s.Name()] {
+			sources = append(sources, s)
+		}
+	}
+	return sources
+}
+
+// enabledIdentitySource returns the identity source registered under name, nil if there is none or it is disabled
+func (f *fetcher) enabledIdentitySource(name string) IdentitySource {
+	for _, s := range f.enabledIdentitySources() {
+		if s.Name() == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (f *fetcher) enabledConnectionSources() []ConnectionSource {
+	f.sourceMu.RLock()
+	defer f.sourceMu.RUnlock()
+
+	var sources []ConnectionSource
+	for _, s := range f.connectionSources {
+		if !f.disabledSources[s.Name()] {
+			sources = append(sources, s)
+		}
+	}
+	return sources
+}
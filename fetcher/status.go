@@ -0,0 +1,206 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SourceState summarizes the outcome of querying a single data source
+type SourceState string
+
+const (
+	SourceSuccess SourceState = "success"
+	SourceEmpty   SourceState = "empty"
+	SourceError   SourceState = "error"
+)
+
+// ErrorKind classifies why a data source failed
+type ErrorKind string
+
+const (
+	ErrKindNone     ErrorKind = ""
+	ErrKindTimeout  ErrorKind = "timeout"
+	ErrKindCanceled ErrorKind = "canceled"
+	ErrKindNetwork  ErrorKind = "network"
+	ErrKindHTTP     ErrorKind = "http"
+	ErrKindDecode   ErrorKind = "decode"
+	// ErrKindUnavailable means the source was not queried because its circuit breaker is open
+	ErrKindUnavailable ErrorKind = "unavailable"
+	ErrKindUnknown     ErrorKind = "unknown"
+)
+
+// SourceStatus reports how a data source behaved during one FetchIdentity or FetchConnections call
+// so that "no profile" (SourceEmpty) can be told apart from "upstream failed" (SourceError)
+type SourceStatus struct {
+	Source  string
+	State   SourceState
+	ErrKind ErrorKind
+	Err     error
+	Msg     string
+	Latency time.Duration
+	// HTTPStatus is the status code of the last upstream response, 0 if none was received
+	HTTPStatus int
+	// Retries is the number of requests of the source that were retried after a failed attempt
+	Retries int
+	// CacheHit is set when the result was served from the cache, Stale when it was past its TTL
+	// and is being refreshed in the background
+	CacheHit bool
+	Stale    bool
+	// Truncated is set when a connection source stopped paging at its WithMaxEdges cap
+	Truncated bool
+}
+
+// HTTPError is returned by sendRequest when the upstream answers with a non-200 status code
+type HTTPError struct {
+	StatusCode int
+	URL        string
+	// RetryAfter is the wait requested by the upstream through the Retry-After header, 0 if absent
+	RetryAfter time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("Response code: %d", e.StatusCode)
+}
+
+// RequiredSourceError is returned alongside the results when a source marked as required failed
+type RequiredSourceError struct {
+	Failed []SourceStatus
+}
+
+func (e *RequiredSourceError) Error() string {
+	var msgs []string
+	for _, s := range e.Failed {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", s.Source, s.Err))
+	}
+	return "required source failed: " + strings.Join(msgs, "; ")
+}
+
+// requestStats collects per-source request metadata, it travels with the context handed to a source
+type requestStats struct {
+	mu         sync.Mutex
+	httpStatus int
+	retries    int
+}
+
+type requestStatsKey struct{}
+
+func withRequestStats(ctx context.Context, stats *requestStats) context.Context {
+	return context.WithValue(ctx, requestStatsKey{}, stats)
+}
+
+func requestStatsFrom(ctx context.Context) *requestStats {
+	stats, _ := ctx.Value(requestStatsKey{}).(*requestStats)
+	return stats
+}
+
+func recordHTTPStatus(ctx context.Context, code int) {
+	if stats := requestStatsFrom(ctx); stats != nil {
+		stats.mu.Lock()
+		stats.httpStatus = code
+		stats.mu.Unlock()
+	}
+}
+
+func recordRetry(ctx context.Context) {
+	if stats := requestStatsFrom(ctx); stats != nil {
+		stats.mu.Lock()
+		stats.retries++
+		stats.mu.Unlock()
+	}
+}
+
+func newSourceStatus(source string, err error, msg string, empty bool, latency time.Duration, stats *requestStats) SourceStatus {
+	status := SourceStatus{
+		Source:  source,
+		State:   SourceSuccess,
+		Err:     err,
+		Msg:     msg,
+		Latency: latency,
+	}
+	if stats != nil {
+		stats.mu.Lock()
+		status.HTTPStatus = stats.httpStatus
+		status.Retries = stats.retries
+		stats.mu.Unlock()
+	}
+	switch {
+	case err != nil:
+		status.State = SourceError
+		status.ErrKind = classifyError(err)
+	case empty:
+		status.State = SourceEmpty
+	}
+	return status
+}
+
+// pendingSourceStatus reports a source that had not answered when ctx was done
+func pendingSourceStatus(source string, ctx context.Context, latency time.Duration) SourceStatus {
+	return SourceStatus{
+		Source:  source,
+		State:   SourceError,
+		ErrKind: classifyError(ctx.Err()),
+		Err:     ctx.Err(),
+		Msg:     "source did not answer before the context was done",
+		Latency: latency,
+	}
+}
+
+func classifyError(err error) ErrorKind {
+	var httpErr *HTTPError
+	var netErr net.Error
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case err == nil:
+		return ErrKindNone
+	case errors.Is(err, ErrSourceUnavailable):
+		return ErrKindUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrKindTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrKindCanceled
+	case errors.As(err, &httpErr):
+		return ErrKindHTTP
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrKindTimeout
+	case errors.As(err, &netErr):
+		return ErrKindNetwork
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return ErrKindDecode
+	default:
+		return ErrKindUnknown
+	}
+}
+
+// RequireSources makes FetchIdentity and FetchConnections return a *RequiredSourceError when any of the
+// named sources fails, the data gathered from the other sources is still returned
+func (f *fetcher) RequireSources(names ...string) {
+	f.sourceMu.Lock()
+	defer f.sourceMu.Unlock()
+	for _, name := range names {
+		f.requiredSources[name] = true
+	}
+}
+
+func (f *fetcher) checkRequiredSources(statuses []SourceStatus) error {
+	f.sourceMu.RLock()
+	defer f.sourceMu.RUnlock()
+
+	var failed []SourceStatus
+	for _, s := range statuses {
+		if s.State == SourceError && f.requiredSources[s.Source] {
+			failed = append(failed, s)
+		}
+	}
+	if len(failed) > 0 {
+		return &RequiredSourceError{Failed: failed}
+	}
+	return nil
+}
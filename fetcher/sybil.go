@@ -0,0 +1,138 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sybilListRefresh is how often a Sybil list read from a URL is downloaded again, snapshots are read once
+const sybilListRefresh = time.Hour
+
+// WithSybilList reads the Sybil verified-addresses list from location instead of SybilUrl,
+// location is either an http(s) URL or the path of a local snapshot of verified.json for offline use
+func WithSybilList(location string) Option {
+	return func(f *fetcher) {
+		f.sybil = &sybilList{location: location}
+	}
+}
+
+// SybilList is the verified.json of the Sybil list, keyed by checksummed address
+type SybilList map[string]struct {
+	Twitter struct {
+		// Timestamp is the verification time in milliseconds since the epoch
+		Timestamp int64  `json:"timestamp"`
+		TweetID   string `json:"tweetID"`
+		Handle    string `json:"handle"`
+	} `json:"twitter"`
+}
+
+// sybilList keeps the Sybil list in memory, keyed by lowercase address, concurrent callers share one load
+type sybilList struct {
+	location string
+
+	mu       sync.Mutex
+	entries  map[string]UserTwitterIdentity
+	loadedAt time.Time
+	loading  chan struct{}
+}
+
+func (l *sybilList) isURL() bool {
+	return strings.HasPrefix(l.location, "http://") || strings.HasPrefix(l.location, "https://")
+}
+
+// get returns the list, loading it with load when it is missing or due for a refresh
+// a failed refresh keeps serving the list loaded before
+func (l *sybilList) get(ctx context.Context, load func(ctx context.Context) (map[string]UserTwitterIdentity, error)) (map[string]UserTwitterIdentity, error) {
+	for {
+		l.mu.Lock()
+		if l.entries != nil && (!l.isURL() || time.Since(l.loadedAt) < sybilListRefresh) {
+			entries := l.entries
+			l.mu.Unlock()
+			return entries, nil
+		}
+		if l.loading != nil {
+			loading := l.loading
+			l.mu.Unlock()
+			select {
+			case <-loading:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		loading := make(chan struct{})
+		l.loading = loading
+		l.mu.Unlock()
+
+		entries, err := load(ctx)
+
+		l.mu.Lock()
+		l.loading = nil
+		if err == nil {
+			l.entries = entries
+			l.loadedAt = time.Now()
+		} else if l.entries != nil {
+			entries, err = l.entries, nil
+		}
+		l.mu.Unlock()
+		close(loading)
+		return entries, err
+	}
+}
+
+// loadSybilList downloads or reads the Sybil list
+func (f *fetcher) loadSybilList(ctx context.Context) (map[string]UserTwitterIdentity, error) {
+	var body []byte
+	var err error
+	if f.sybil.isURL() {
+		body, err = f.sendRequest(ctx, RequestArgs{
+			source: SYBIL,
+			url:    f.sybil.location,
+			method: "GET",
+		})
+	} else {
+		body, err = ioutil.ReadFile(f.sybil.location)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := SybilList{}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, err
+	}
+	entries := make(map[string]UserTwitterIdentity, len(list))
+	for address, entry := range list {
+		if entry.Twitter.Handle == "" {
+			continue
+		}
+		entries[strings.ToLower(address)] = UserTwitterIdentity{
+			Handle:     entry.Twitter.Handle,
+			VerifiedAt: time.Unix(0, entry.Twitter.Timestamp*int64(time.Millisecond)).UTC(),
+			TweetID:    entry.Twitter.TweetID,
+			DataSource: SYBIL,
+		}
+	}
+	return entries, nil
+}
+
+// processSybil looks address up in the Sybil list, where addresses are verified by a tweet of their owner
+func (f *fetcher) processSybil(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	entries, err := f.sybil.get(ctx, f.loadSybilList)
+	if err != nil {
+		result.Err = err
+		result.Msg = "[processSybil] load verified list failed"
+		return result
+	}
+
+	if entry, ok := entries[strings.ToLower(address)]; ok {
+		result.Twitter = &entry
+	}
+	return result
+}
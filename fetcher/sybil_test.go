@@ -0,0 +1,99 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchIdentitySybil(t *testing.T) {
+	srv := newTestServer(t)
+	var calls int32
+	srv.Handle("/Uniswap/sybil-list/master/verified.json", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.ServeFile(w, r, "testdata/sybil_verified.json")
+	})
+	f := newTestFetcher(t, srv)
+
+	want := UserTwitterIdentity{
+		Handle:     "BrantlyMillegan",
+		VerifiedAt: time.Date(2021, 1, 15, 17, 52, 3, 456000000, time.UTC),
+		TweetID:    "1350130958618046465",
+		DataSource: SYBIL,
+	}
+	for i := 0; i < 2; i++ {
+		ids, err := f.FetchIdentity(testAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids.Twitter) != 1 || ids.Twitter[0] != want {
+			t.Errorf("Twitter = %+v, want %+v", ids.Twitter, want)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("list downloaded %d times, want 1", n)
+	}
+
+	if entry := f.processSybil(context.Background(), "0x0000000000000000000000000000000000000001"); !entry.isEmpty() || entry.Err != nil {
+		t.Errorf("entry = %+v for an address not on the list, want empty", entry)
+	}
+}
+
+func TestSybilSnapshot(t *testing.T) {
+	// no upstream at all, the batch jobs run offline
+	f := NewFetcher(WithSybilList("testdata/sybil_verified.json"))
+	entry := f.processSybil(context.Background(), "0xd8da6bf26964af9d7eed9e03e53415d37aa96045")
+	if entry.Err != nil {
+		t.Fatal(entry.Err)
+	}
+	if entry.Twitter == nil || entry.Twitter.Handle != "VitalikButerin" || entry.Twitter.TweetID != "1350042318475329536" {
+		t.Errorf("Twitter = %+v", entry.Twitter)
+	}
+
+	f = NewFetcher(WithSybilList("testdata/missing.json"))
+	if entry := f.processSybil(context.Background(), testAddress); entry.Err == nil {
+		t.Error("reading a missing snapshot succeeded")
+	}
+}
+
+func TestSybilListLoad(t *testing.T) {
+	l := &sybilList{location: SybilUrl}
+	var loads int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (map[string]UserTwitterIdentity, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return map[string]UserTwitterIdentity{testAddress: {Handle: "old"}}, nil
+	}
+
+	// concurrent callers share one load
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if entries, err := l.get(context.Background(), load); err != nil || entries[testAddress].Handle != "old" {
+				t.Errorf("get = %v, %v", entries, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if loads != 1 {
+		t.Errorf("list loaded %d times, want 1", loads)
+	}
+
+	// a failed refresh keeps the list loaded before
+	l.loadedAt = time.Now().Add(-2 * sybilListRefresh)
+	entries, err := l.get(context.Background(), func(ctx context.Context) (map[string]UserTwitterIdentity, error) {
+		return nil, errors.New("unreachable")
+	})
+	if err != nil || entries[testAddress].Handle != "old" {
+		t.Errorf("get after failed refresh = %v, %v, want the previous list", entries, err)
+	}
+}
@@ -0,0 +1,195 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// TEZOS_LINK_EXPLICIT marks Tezos links given with WithTezosLink
+const TEZOS_LINK_EXPLICIT = "Explicit"
+
+// tzktPageSize is the largest page TzKT serves
+const tzktPageSize = 10000
+
+const tzprofileQuery = `query Tzprofile($account: String!) {
+	tzprofiles_by_pk(account: $account) {
+		alias description website twitter discord github logo ethereum
+	}
+}`
+
+var tezosAddressRe = regexp.MustCompile(`(tz1|tz2|tz3|KT1)[1-9A-HJ-NP-Za-km-z]{33}`)
+
+// WithTezosLink links the Ethereum address eth to the Tezos account tz, the TEZOS source then reports tz
+// for eth besides the accounts found through Showtime
+func WithTezosLink(eth, tz string) Option {
+	return func(f *fetcher) {
+		key := strings.ToLower(eth)
+		f.tezosLinks[key] = append(f.tezosLinks[key], tz)
+	}
+}
+
+type tezosLinksKey struct{}
+
+// ContextWithTezosLinks links the address queried with ctx to the Tezos accounts tz for that call only,
+// e.g. accounts the caller collected from its own users, FetchIdentityWithContext then reports them like WithTezosLink
+func ContextWithTezosLinks(ctx context.Context, tz ...string) context.Context {
+	links := append(append([]string(nil), tezosCallLinks(ctx)...), tz...)
+	return context.WithValue(ctx, tezosLinksKey{}, links)
+}
+
+func tezosCallLinks(ctx context.Context) []string {
+	links, _ := ctx.Value(tezosLinksKey{}).([]string)
+	return links
+}
+
+// isTezosAddress reports whether address is a Tezos implicit or contract account
+func isTezosAddress(address string) bool {
+	return len(address) == 36 && tezosAddressRe.MatchString(address)
+}
+
+// tezosAddressFromHandle returns the Tezos account in a Showtime hic et nunc link, e.g. "hicetnunc.xyz/tz1...",
+// links holding a subjkt name instead are not resolved
+func tezosAddressFromHandle(handle string) string {
+	for _, field := range strings.FieldsFunc(handle, func(r rune) bool { return r == '/' }) {
+		if isTezosAddress(field) {
+			return field
+		}
+	}
+	return ""
+}
+
+func (f *fetcher) getTezosObjkts(ctx context.Context, tz string) ([]TezosToken, error) {
+	var objkts []TezosToken
+	for offset := 0; ; offset += tzktPageSize {
+		body, err := f.sendRequest(ctx, RequestArgs{
+			source: TEZOS,
+			url:    TzktUrl + "/tokens/balances",
+			method: "GET",
+			params: map[string]string{
+				"account":        tz,
+				"token.contract": HenObjktContract,
+				"balance.gt":     "0",
+				"limit":          fmt.Sprint(tzktPageSize),
+				"offset":         fmt.Sprint(offset),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		var page []TzktTokenBalance
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+		for _, balance := range page {
+			objkts = append(objkts, TezosToken{
+				Contract:   balance.Token.Contract.Address,
+				TokenID:    balance.Token.TokenID,
+				Name:       balance.Token.Metadata.Name,
+				DisplayURI: balance.Token.Metadata.DisplayURI,
+				Balance:    balance.Balance,
+			})
+		}
+		if len(page) < tzktPageSize {
+			return objkts, nil
+		}
+	}
+}
+
+// getTezosIdentity fetches the TzKT account, Tezos Profile and OBJKTs of tz linked to the Ethereum address
+func (f *fetcher) getTezosIdentity(ctx context.Context, address, tz, linkedVia string) (*UserTezosIdentity, error) {
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: TEZOS,
+		url:    TzktUrl + "/accounts/" + tz,
+		method: "GET",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var account TzktAccount
+	if err := json.Unmarshal(body, &account); err != nil {
+		return nil, err
+	}
+
+	var profile Tzprofile
+	err = f.graphQLQuery(ctx, TEZOS, TzprofilesUrl, tzprofileQuery, map[string]interface{}{"account": tz}, &profile)
+	if err != nil {
+		return nil, err
+	}
+
+	objkts, err := f.getTezosObjkts(ctx, tz)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &UserTezosIdentity{
+		Address:       tz,
+		LinkedVia:     linkedVia,
+		Alias:         account.Alias,
+		Balance:       account.Balance,
+		FirstActivity: account.FirstActivityTime,
+		Objkts:        objkts,
+		DataSource:    TEZOS,
+	}
+	if p := profile.Profile; p != nil {
+		identity.Verified = strings.EqualFold(p.Ethereum, address)
+		identity.Name = p.Alias
+		identity.Description = p.Description
+		identity.Website = p.Website
+		identity.Twitter = p.Twitter
+		identity.Discord = p.Discord
+		identity.Github = p.Github
+		identity.Logo = p.Logo
+	}
+	return identity, nil
+}
+
+// processTezos reports the Tezos accounts linked to address, through its Showtime hic et nunc link,
+// WithTezosLink or ContextWithTezosLinks, one entry per account
+func (f *fetcher) processTezos(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	// explicit links come first, an account also linked on Showtime is reported once
+	linkedVia := make(map[string]string)
+	var accounts []string
+	for _, tz := range append(f.tezosLinks[strings.ToLower(address)], tezosCallLinks(ctx)...) {
+		if _, ok := linkedVia[tz]; !ok {
+			linkedVia[tz] = TEZOS_LINK_EXPLICIT
+			accounts = append(accounts, tz)
+		}
+	}
+
+	// the Showtime profile is the one queried for the SHOWTIME source, it is skipped when that source is disabled
+	if src := f.enabledIdentitySource(SHOWTIME); src != nil {
+		showtime := f.queryIdentitySourceOnce(ctx, src, address).entry
+		switch {
+		case showtime.Err != nil:
+			// the explicit links are still reported, the error keeps the degraded result out of the cache
+			zap.L().With(zap.Error(showtime.Err)).Warn("[processTezos] showtime link lookup failed")
+			result.Err = showtime.Err
+			result.Msg = "[processTezos] showtime link lookup failed, only explicit links are reported"
+		case showtime.Showtime != nil:
+			if tz := tezosAddressFromHandle(showtime.Showtime.HicetnuncHandle); tz != "" {
+				if _, ok := linkedVia[tz]; !ok {
+					linkedVia[tz] = SHOWTIME
+					accounts = append(accounts, tz)
+				}
+			}
+		}
+	}
+
+	for _, tz := range accounts {
+		identity, err := f.getTezosIdentity(ctx, address, tz, linkedVia[tz])
+		if err != nil {
+			result.Err = err
+			result.Msg = "[processTezos] fetch tezos account failed"
+			return result
+		}
+		result.Tezos = append(result.Tezos, *identity)
+	}
+	return result
+}
@@ -0,0 +1,192 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyberconnecthq/indexer/fetcher/fakeupstream"
+)
+
+func TestTezosAddressFromHandle(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"tz1eY5Aqa1kXDFoiebL28emyXFoneAoVg1zh", "tz1eY5Aqa1kXDFoiebL28emyXFoneAoVg1zh"},
+		{"https://hicetnunc.xyz/tz1eY5Aqa1kXDFoiebL28emyXFoneAoVg1zh/", "tz1eY5Aqa1kXDFoiebL28emyXFoneAoVg1zh"},
+		{"KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton", "KT1RJ6PbjHpwc3M5rw5s2Nbmefwbuwbdxton"},
+		{"brantly", ""},
+		{"tz1eY5Aqa1kXDFoiebL28emyXFoneAoVg1zhX", ""},
+		{"tz4eY5Aqa1kXDFoiebL28emyXFoneAoVg1zh", ""},
+	}
+	for _, tt := range tests {
+		if got := tezosAddressFromHandle(tt.in); got != tt.want {
+			t.Errorf("tezosAddressFromHandle(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTezosExplicitLink(t *testing.T) {
+	srv := newTestServer(t)
+	const explicit = "tz2VQnRmCjJz2ymWnx1kNQhuHH1rGuaBmCH2"
+	srv.Handle("/v1/accounts/"+explicit, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"address": %q, "balance": 1}`, explicit)
+	})
+	srv.Handle("/v1/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]string `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Variables["account"] == explicit {
+			fmt.Fprint(w, `{"data": {"tzprofiles_by_pk": null}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"tzprofiles_by_pk": {"ethereum": %q}}}`, testAddress)
+	})
+	srv.Handle("/v1/tokens/balances", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	f := newTestFetcher(t, srv,
+		// the Showtime account is linked explicitly too, it is reported once
+		WithTezosLink("0x983110309620D911731AC0932219AF06091B6744", explicit),
+		WithTezosLink(testAddress, "tz1eY5Aqa1kXDFoiebL28emyXFoneAoVg1zh"),
+	)
+
+	entry := f.processTezos(context.Background(), testAddress)
+	if entry.Err != nil {
+		t.Fatal(entry.Err)
+	}
+	if len(entry.Tezos) != 2 {
+		t.Fatalf("Tezos = %+v", entry.Tezos)
+	}
+	if tz := entry.Tezos[0]; tz.Address != explicit || tz.LinkedVia != TEZOS_LINK_EXPLICIT || tz.Verified {
+		t.Errorf("explicit link = %+v", tz)
+	}
+	if tz := entry.Tezos[1]; tz.LinkedVia != TEZOS_LINK_EXPLICIT || !tz.Verified {
+		t.Errorf("explicit and Showtime link = %+v", tz)
+	}
+}
+
+func TestTezosSharesShowtimeRequest(t *testing.T) {
+	srv := newTestServer(t)
+	fixture, err := fakeupstream.ReadFixture(filepath.Join(fixtureDir, "GET_api_v2_profile_server_"+testAddress+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hits int32
+	srv.Handle("/api/v2/profile_server/"+testAddress, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Write(fixture.Body)
+	})
+	f := newTestFetcher(t, srv)
+
+	ids, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hits := atomic.LoadInt32(&hits); hits != 1 || len(ids.Tezos) != 1 || ids.Tezos[0].LinkedVia != SHOWTIME {
+		t.Errorf("%d Showtime requests, Tezos = %+v, want one request and the Showtime link", hits, ids.Tezos)
+	}
+}
+
+// tezosAccounts makes srv serve the TzKT account of every tz without profile nor OBJKTs, counting the account requests
+func tezosAccounts(srv *fakeupstream.Server, hits *int32, tz ...string) {
+	for _, account := range tz {
+		account := account
+		srv.Handle("/v1/accounts/"+account, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(hits, 1)
+			fmt.Fprintf(w, `{"address": %q}`, account)
+		})
+	}
+	srv.Handle("/v1/graphql", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"tzprofiles_by_pk": null}}`)
+	})
+	srv.Handle("/v1/tokens/balances", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+}
+
+func TestTezosShowtimeFailureKeepsExplicitLinks(t *testing.T) {
+	const explicit = "tz2VQnRmCjJz2ymWnx1kNQhuHH1rGuaBmCH2"
+	for _, disabled := range []bool{false, true} {
+		srv := newTestServer(t)
+		srv.Handle("/api/v2/profile_server/"+testAddress, func(w http.ResponseWriter, r *http.Request) {
+			if disabled {
+				t.Error("Showtime queried while disabled")
+			}
+			w.WriteHeader(http.StatusNotFound)
+		})
+		var hits int32
+		tezosAccounts(srv, &hits, explicit)
+		opts := []Option{WithTezosLink(testAddress, explicit)}
+		if disabled {
+			opts = append(opts, WithDisabledSources(SHOWTIME))
+		}
+		f := newTestFetcher(t, srv, opts...)
+
+		entry := f.processTezos(context.Background(), testAddress)
+		if disabled != (entry.Err == nil) || len(entry.Tezos) != 1 || entry.Tezos[0].Address != explicit {
+			t.Errorf("Showtime disabled %v: entry = %+v, want the explicit link, with an error unless disabled", disabled, entry)
+		}
+	}
+}
+
+func TestTezosDegradedResultNotCached(t *testing.T) {
+	const explicit = "tz2VQnRmCjJz2ymWnx1kNQhuHH1rGuaBmCH2"
+	srv := newTestServer(t)
+	srv.Handle("/api/v2/profile_server/"+testAddress, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	var hits int32
+	tezosAccounts(srv, &hits, explicit)
+	f := newTestFetcher(t, srv, WithTezosLink(testAddress, explicit), WithCache(NewLRUCache(100), CacheTTL{TTL: time.Hour}))
+
+	for i := 0; i < 2; i++ {
+		ids, _ := f.FetchIdentity(testAddress)
+		if len(ids.Tezos) != 1 || statusOf(t, ids.Status, TEZOS).State != SourceError {
+			t.Errorf("call %d: Tezos = %+v, status %+v, want the explicit link reported with the error", i, ids.Tezos, statusOf(t, ids.Status, TEZOS))
+		}
+	}
+	if hits := atomic.LoadInt32(&hits); hits != 2 {
+		t.Errorf("%d account requests, want the degraded result fetched again rather than cached", hits)
+	}
+}
+
+func TestTezosCallLinks(t *testing.T) {
+	const static, perCall = "tz2VQnRmCjJz2ymWnx1kNQhuHH1rGuaBmCH2", "tz1VSUr8wwNhLAzempoch5d6hLRiTh8Cjcjb"
+	srv := newTestServer(t)
+	var hits int32
+	tezosAccounts(srv, &hits, static, perCall)
+	f := newTestFetcher(t, srv, WithTezosLink(testAddress, static), WithDisabledSources(SHOWTIME),
+		WithCache(NewLRUCache(100), CacheTTL{TTL: time.Hour}))
+
+	accounts := func(ids IdentityEntryList) []string {
+		var tz []string
+		for _, account := range ids.Tezos {
+			tz = append(tz, account.Address)
+		}
+		return tz
+	}
+	ids, _ := f.FetchIdentityWithContext(ContextWithTezosLinks(context.Background(), perCall), testAddress)
+	if got := fmt.Sprint(accounts(ids)); got != fmt.Sprint([]string{static, perCall}) {
+		t.Errorf("with a per-call link: Tezos accounts %s", got)
+	}
+	if ids.Tezos[1].LinkedVia != TEZOS_LINK_EXPLICIT {
+		t.Errorf("per-call link = %+v", ids.Tezos[1])
+	}
+
+	// the per-call link neither leaks into nor is hidden by the cached result of other calls
+	ids, _ = f.FetchIdentity(testAddress)
+	if got := fmt.Sprint(accounts(ids)); got != fmt.Sprint([]string{static}) {
+		t.Errorf("without a per-call link: Tezos accounts %s", got)
+	}
+	ids, _ = f.FetchIdentityWithContext(ContextWithTezosLinks(context.Background(), perCall), testAddress)
+	if got := fmt.Sprint(accounts(ids)); got != fmt.Sprint([]string{static, perCall}) {
+		t.Errorf("with a per-call link after a cached call: Tezos accounts %s", got)
+	}
+}
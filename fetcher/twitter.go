@@ -0,0 +1,222 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var twitterHandleRe = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+
+// TwitterLookup finds the Twitter account of a handle, it backs the Twitter enrichment of FetchIdentity
+type TwitterLookup interface {
+	// LookupUser returns the account of handle, nil if no account has that handle
+	LookupUser(ctx context.Context, handle string) (*TwitterUser, error)
+}
+
+// WithTwitterLookup enriches the discovered Twitter handles with l, e.g. a local stand-in in tests
+// by default the handles are looked up on the Twitter API once a bearer token is set with WithAPIKey(TWITTER, token)
+func WithTwitterLookup(l TwitterLookup) Option {
+	return func(f *fetcher) {
+		f.twitterLookup = l
+	}
+}
+
+// setupTwitterLookup uses the Twitter API when a bearer token is configured and no lookup was given
+func (f *fetcher) setupTwitterLookup() {
+	if f.twitterLookup == nil && f.apiKey(TWITTER) != "" {
+		f.twitterLookup = &twitterAPI{f: f}
+	}
+}
+
+// twitterAPI is the TwitterLookup backed by the Twitter API v2, its requests go out with the options of TWITTER
+type twitterAPI struct {
+	f *fetcher
+}
+
+func (t *twitterAPI) LookupUser(ctx context.Context, handle string) (*TwitterUser, error) {
+	body, err := t.f.sendRequest(ctx, RequestArgs{
+		source: TWITTER,
+		url:    fmt.Sprintf(TwitterUrl, handle),
+		method: "GET",
+		header: map[string]string{"Authorization": "Bearer " + t.f.apiKey(TWITTER)},
+		params: map[string]string{
+			"user.fields":  "description,public_metrics,pinned_tweet_id",
+			"expansions":   "pinned_tweet_id",
+			"tweet.fields": "text",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TwitterUserResp
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		// unknown handles are reported as errors of an otherwise successful response
+		for _, e := range resp.Errors {
+			if !strings.HasSuffix(e.Type, "resource-not-found") {
+				return nil, errors.New("twitter: " + e.Detail)
+			}
+		}
+		return nil, nil
+	}
+
+	user := &TwitterUser{
+		ID:             resp.Data.ID,
+		Username:       resp.Data.Username,
+		Name:           resp.Data.Name,
+		Bio:            resp.Data.Description,
+		FollowersCount: resp.Data.PublicMetrics.FollowersCount,
+		FollowingCount: resp.Data.PublicMetrics.FollowingCount,
+		TweetCount:     resp.Data.PublicMetrics.TweetCount,
+	}
+	for _, tweet := range resp.Includes.Tweets {
+		if tweet.ID == resp.Data.PinnedTweetID {
+			user.PinnedTweet = tweet.Text
+		}
+	}
+	return user, nil
+}
+
+// twitterHandles collects the Twitter handles found by the identity sources, keyed by lowercase handle,
+// with the sources each was found on
+func twitterHandles(ids *IdentityEntryList) (handles map[string]string, via map[string][]string) {
+	handles = make(map[string]string)
+	via = make(map[string][]string)
+	add := func(raw, source string) {
+		if strings.TrimSpace(raw) == "" {
+			return
+		}
+		handle := convertTwitterHandle(strings.TrimSpace(raw))
+		if !twitterHandleRe.MatchString(handle) {
+			return
+		}
+		key := strings.ToLower(handle)
+		handles[key] = handle
+		for _, s := range via[key] {
+			if s == source {
+				return
+			}
+		}
+		via[key] = append(via[key], source)
+	}
+
+	for _, t := range ids.Twitter {
+		add(t.Handle, t.DataSource)
+	}
+	for _, s := range ids.Superrare {
+		add(s.TwitterLink, s.DataSource)
+	}
+	for _, fnd := range ids.Foundation {
+		add(fnd.Twitter, fnd.DataSource)
+		add(fnd.VerifiedTwitter, fnd.DataSource)
+	}
+	for _, s := range ids.Showtime {
+		add(s.TwitterHandle, s.DataSource)
+	}
+	for _, e := range ids.EnsProfile {
+		add(e.Twitter, e.DataSource)
+	}
+	return handles, via
+}
+
+// mentions reports whether text names term as a whole word, case-insensitively
+func mentions(text, term string) bool {
+	if term == "" {
+		return false
+	}
+	text, term = strings.ToLower(text), strings.ToLower(term)
+	for from := 0; ; {
+		i := strings.Index(text[from:], term)
+		if i < 0 {
+			return false
+		}
+		i += from
+		end := i + len(term)
+		// a name right after a dot is a subdomain of another name
+		if (i == 0 || !wordByte(text[i-1], ".-")) && (end == len(text) || !wordByte(text[end], "-")) {
+			return true
+		}
+		from = i + 1
+	}
+}
+
+// wordByte reports whether b continues a lowercase word, the characters in also count as part of it
+func wordByte(b byte, also string) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || strings.IndexByte(also, b) >= 0
+}
+
+// enrichTwitter looks up every Twitter handle found by the identity sources and checks whether the account
+// mentions the address or one of its names, the outcome is reported in the status of TWITTER
+// it is turned off like a source with WithDisabledSources(TWITTER) and runs behind the TWITTER circuit breaker
+func (f *fetcher) enrichTwitter(ctx context.Context, ids *IdentityEntryList) {
+	if f.twitterLookup == nil || f.isSourceDisabled(TWITTER) {
+		return
+	}
+	handles, via := twitterHandles(ids)
+	keys := make([]string, 0, len(handles))
+	for key := range handles {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	names := []string{ids.Name, ids.Ens}
+	for _, name := range ids.Names {
+		names = append(names, name.Name)
+	}
+
+	stats := &requestStats{}
+	begin := time.Now()
+	var firstErr error
+	var msg string
+	b := f.breaker(TWITTER)
+	for _, key := range keys {
+		if b != nil && !b.allow() {
+			if firstErr == nil {
+				firstErr = ErrSourceUnavailable
+				msg = "[enrichTwitter] skipped, circuit breaker open"
+			}
+			break
+		}
+		user, err := f.twitterLookup.LookupUser(withRequestStats(ctx, stats), handles[key])
+		if b != nil {
+			b.record(breakerOutcomeOf(ctx, err))
+		}
+		if err != nil {
+			zap.L().With(zap.Error(err), zap.String("handle", handles[key])).Error("twitter lookup failed")
+			if firstErr == nil {
+				firstErr = err
+				msg = "[enrichTwitter] lookup of " + handles[key] + " failed"
+			}
+			continue
+		}
+		if user == nil {
+			continue
+		}
+
+		profile := UserTwitterProfile{
+			TwitterUser:   *user,
+			DiscoveredVia: via[key],
+			DataSource:    TWITTER,
+		}
+		for _, text := range []string{user.Bio, user.PinnedTweet} {
+			profile.MentionsAddress = profile.MentionsAddress || mentions(text, ids.Address)
+			for _, name := range names {
+				profile.MentionsName = profile.MentionsName || mentions(text, name)
+			}
+		}
+		ids.TwitterProfiles = append(ids.TwitterProfiles, profile)
+	}
+
+	ids.Status = append(ids.Status, newSourceStatus(TWITTER, firstErr, msg, len(ids.TwitterProfiles) == 0, time.Since(begin), stats))
+}
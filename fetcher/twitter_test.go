@@ -0,0 +1,194 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubTwitter stands in for the Twitter API, users are keyed by lowercase handle
+type stubTwitter map[string]TwitterUser
+
+func (s stubTwitter) LookupUser(ctx context.Context, handle string) (*TwitterUser, error) {
+	if handle == "broken" {
+		return nil, errors.New("rate limited")
+	}
+	user, ok := s[strings.ToLower(handle)]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+func TestFetchIdentityTwitterEnrichment(t *testing.T) {
+	srv := newTestServer(t)
+	user := TwitterUser{
+		ID:             "17291227",
+		Username:       "BrantlyMillegan",
+		Name:           "brantly.eth",
+		Bio:            "Director of Operations at @ensdomains. brantly.eth",
+		FollowersCount: 24000,
+		PinnedTweet:    "my address is " + testAddress,
+	}
+	f := newTestFetcher(t, srv, WithTwitterLookup(stubTwitter{"brantlymillegan": user}))
+
+	ids, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := UserTwitterProfile{
+		TwitterUser:     user,
+		MentionsAddress: true,
+		MentionsName:    true,
+		DiscoveredVia:   []string{SYBIL, SUPERRARE, FOUNDATION, SHOWTIME},
+		DataSource:      TWITTER,
+	}
+	if len(ids.TwitterProfiles) != 1 || fmt.Sprint(ids.TwitterProfiles[0]) != fmt.Sprint(want) {
+		t.Errorf("TwitterProfiles = %+v, want %+v", ids.TwitterProfiles, want)
+	}
+	if s := statusOf(t, ids.Status, TWITTER); s.State != SourceSuccess {
+		t.Errorf("Twitter status = %+v", s)
+	}
+}
+
+func TestTwitterEnrichmentDisabled(t *testing.T) {
+	srv := newTestServer(t)
+	f := newTestFetcher(t, srv)
+
+	ids, err := f.FetchIdentity(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids.TwitterProfiles) != 0 || len(ids.Status) != len(testSources) {
+		t.Errorf("TwitterProfiles = %+v with %d statuses, want no enrichment", ids.TwitterProfiles, len(ids.Status))
+	}
+}
+
+func TestEnrichTwitterLookupError(t *testing.T) {
+	f := NewFetcher(WithTwitterLookup(stubTwitter{"brantly": {Username: "brantly"}}))
+	ids := IdentityEntryList{
+		Address:  testAddress,
+		Twitter:  []UserTwitterIdentity{{Handle: "broken", DataSource: SYBIL}},
+		Showtime: []UserShowtimeIdentity{{TwitterHandle: "@brantly", DataSource: SHOWTIME}},
+		// links reduced to nothing are skipped
+		Superrare:  []UserSuperrareIdentity{{TwitterLink: "/", DataSource: SUPERRARE}},
+		Foundation: []UserFoundationIdentity{{Twitter: "@/", DataSource: FOUNDATION}},
+	}
+
+	f.enrichTwitter(context.Background(), &ids)
+	if len(ids.TwitterProfiles) != 1 || ids.TwitterProfiles[0].MentionsAddress || ids.TwitterProfiles[0].MentionsName {
+		t.Errorf("TwitterProfiles = %+v", ids.TwitterProfiles)
+	}
+	if s := statusOf(t, ids.Status, TWITTER); s.State != SourceError || s.Msg != "[enrichTwitter] lookup of broken failed" {
+		t.Errorf("Twitter status = %+v", s)
+	}
+}
+
+// countingTwitter counts the lookups made through it
+type countingTwitter struct {
+	stubTwitter
+	calls int
+}
+
+func (c *countingTwitter) LookupUser(ctx context.Context, handle string) (*TwitterUser, error) {
+	c.calls++
+	return c.stubTwitter.LookupUser(ctx, handle)
+}
+
+func TestEnrichTwitterDisabledSource(t *testing.T) {
+	lookup := &countingTwitter{stubTwitter: stubTwitter{"brantly": {Username: "brantly"}}}
+	f := NewFetcher(WithTwitterLookup(lookup), WithDisabledSources(TWITTER))
+	ids := IdentityEntryList{
+		Address:  testAddress,
+		Showtime: []UserShowtimeIdentity{{TwitterHandle: "@brantly", DataSource: SHOWTIME}},
+	}
+
+	f.enrichTwitter(context.Background(), &ids)
+	if lookup.calls != 0 || len(ids.TwitterProfiles) != 0 || len(ids.Status) != 0 {
+		t.Errorf("%d lookups, TwitterProfiles = %+v, Status = %+v, want no enrichment", lookup.calls, ids.TwitterProfiles, ids.Status)
+	}
+}
+
+func TestEnrichTwitterBreakerOpen(t *testing.T) {
+	lookup := &countingTwitter{stubTwitter: stubTwitter{"brantly": {Username: "brantly"}}}
+	f := NewFetcher(WithTwitterLookup(lookup), WithBreakerPolicy(BreakerPolicy{FailureThreshold: 1, Cooldown: time.Hour}))
+	f.breaker(TWITTER).record(breakerFailure)
+	ids := IdentityEntryList{
+		Address:  testAddress,
+		Showtime: []UserShowtimeIdentity{{TwitterHandle: "@brantly", DataSource: SHOWTIME}},
+	}
+
+	f.enrichTwitter(context.Background(), &ids)
+	if lookup.calls != 0 || len(ids.TwitterProfiles) != 0 {
+		t.Errorf("%d lookups, TwitterProfiles = %+v, want none behind an open breaker", lookup.calls, ids.TwitterProfiles)
+	}
+	if s := statusOf(t, ids.Status, TWITTER); !errors.Is(s.Err, ErrSourceUnavailable) {
+		t.Errorf("Twitter status = %+v, want the breaker reported", s)
+	}
+}
+
+func TestTwitterAPI(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Handle("/twitter/2/users/by/username/BrantlyMillegan", func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer test-token" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		fmt.Fprint(w, `{"data": {"id": "17291227", "name": "brantly.eth", "username": "BrantlyMillegan",
+			"description": "ENS", "pinned_tweet_id": "2", "public_metrics": {"followers_count": 3, "following_count": 2, "tweet_count": 1}},
+			"includes": {"tweets": [{"id": "2", "text": "pinned"}]}}`)
+	})
+	srv.Handle("/twitter/2/users/by/username/nobody", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"errors": [{"type": "https://api.twitter.com/2/problems/resource-not-found", "detail": "Could not find user"}]}`)
+	})
+	srv.Handle("/twitter/2/users/by/username/suspended", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"errors": [{"type": "https://api.twitter.com/2/problems/resource-not-authorized", "detail": "User has been suspended"}]}`)
+	})
+	f := NewFetcher(WithAPIKey(TWITTER, "test-token"), WithBaseURL(TWITTER, srv.URL+"/twitter"))
+
+	user, err := f.twitterLookup.LookupUser(context.Background(), "BrantlyMillegan")
+	want := TwitterUser{
+		ID:             "17291227",
+		Username:       "BrantlyMillegan",
+		Name:           "brantly.eth",
+		Bio:            "ENS",
+		FollowersCount: 3,
+		FollowingCount: 2,
+		TweetCount:     1,
+		PinnedTweet:    "pinned",
+	}
+	if err != nil || user == nil || *user != want {
+		t.Errorf("LookupUser = %+v, %v, want %+v", user, err, want)
+	}
+	if user, err := f.twitterLookup.LookupUser(context.Background(), "nobody"); user != nil || err != nil {
+		t.Errorf("LookupUser(nobody) = %+v, %v, want not found", user, err)
+	}
+	if _, err := f.twitterLookup.LookupUser(context.Background(), "suspended"); err == nil {
+		t.Error("LookupUser(suspended) succeeded, want an error")
+	}
+}
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		text, term string
+		want       bool
+	}{
+		{"gm, I am brantly.eth", "brantly.eth", true},
+		{"Brantly.ETH.", "brantly.eth", true},
+		{"notbrantly.eth", "brantly.eth", false},
+		{"sub.brantly.eth", "brantly.eth", false},
+		{"(brantly.eth)", "brantly.eth", true},
+		{"brantly.eth-fan and brantly.eth2", "brantly.eth", false},
+		{"brantly.eth-fan, brantly.eth", "brantly.eth", true},
+		{"0x983110309620D911731AC0932219AF06091B6744 is mine", testAddress, true},
+		{"anything", "", false},
+	}
+	for _, tt := range tests {
+		if got := mentions(tt.text, tt.term); got != tt.want {
+			t.Errorf("mentions(%q, %q) = %v, want %v", tt.text, tt.term, got, tt.want)
+		}
+	}
+}
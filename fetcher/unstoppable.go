@@ -0,0 +1,63 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// unstoppablePageSize is the largest page of domains the Resolution API serves
+const unstoppablePageSize = 200
+
+// unstoppableHeader sends the key set with WithAPIKey(UNSTOPPABLE, key), the source is only registered with one
+func (f *fetcher) unstoppableHeader() map[string]string {
+	return map[string]string{"Authorization": "Bearer " + f.apiKey(UNSTOPPABLE)}
+}
+
+// processUnstoppable lists the Unstoppable Domains owned by address, the domain its reverse record points at
+// is its primary name, the domains are minted once and do not expire
+func (f *fetcher) processUnstoppable(ctx context.Context, address string) IdentityEntry {
+	var result IdentityEntry
+
+	startingAfter := ""
+	for {
+		params := map[string]string{"perPage": strconv.Itoa(unstoppablePageSize)}
+		if startingAfter != "" {
+			params["startingAfter"] = startingAfter
+		}
+		body, err := f.sendRequest(ctx, RequestArgs{
+			source: UNSTOPPABLE,
+			url:    fmt.Sprintf("%s/owners/%s/domains", UnstoppableUrl, address),
+			method: "GET",
+			header: f.unstoppableHeader(),
+			params: params,
+		})
+		if err != nil {
+			result.Err = err
+			result.Msg = "[processUnstoppable] fetch domains failed"
+			return result
+		}
+
+		var page UnstoppableDomains
+		if err := json.Unmarshal(body, &page); err != nil {
+			result.Err = err
+			result.Msg = "[processUnstoppable] domains response json unmarshal failed"
+			return result
+		}
+		for _, domain := range page.Data {
+			meta := domain.Attributes.Meta
+			result.Names = append(result.Names, UserName{
+				Name:       meta.Domain,
+				Registry:   UNSTOPPABLE,
+				Primary:    meta.Reverse,
+				Reverse:    meta.Reverse,
+				DataSource: UNSTOPPABLE,
+			})
+		}
+		if !page.Meta.HasMore || page.Meta.NextStartingAfter == "" {
+			return result
+		}
+		startingAfter = page.Meta.NextStartingAfter
+	}
+}
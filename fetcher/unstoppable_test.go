@@ -0,0 +1,64 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestUnstoppablePagination(t *testing.T) {
+	srv := newTestServer(t)
+	var cursors []string
+	srv.Handle("/ud/resolve/owners/"+testAddress+"/domains", func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer test-key" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		cursor := r.URL.Query().Get("startingAfter")
+		cursors = append(cursors, cursor)
+		if cursor == "" {
+			fmt.Fprint(w, `{"data": [{"id": "a.crypto", "attributes": {"meta": {"domain": "a.crypto"}}}],
+				"meta": {"nextStartingAfter": "a.crypto", "hasMore": true}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": [{"id": "b.x", "attributes": {"meta": {"domain": "b.x", "reverse": true}}}],
+			"meta": {"nextStartingAfter": "b.x", "hasMore": false}}`)
+	})
+	f := NewFetcher(WithBaseURL(UNSTOPPABLE, srv.URL+"/ud"), WithAPIKey(UNSTOPPABLE, "test-key"))
+
+	entry := f.processUnstoppable(context.Background(), testAddress)
+	if entry.Err != nil {
+		t.Fatal(entry.Err)
+	}
+	want := []UserName{
+		{Name: "a.crypto", Registry: UNSTOPPABLE, DataSource: UNSTOPPABLE},
+		{Name: "b.x", Registry: UNSTOPPABLE, Primary: true, Reverse: true, DataSource: UNSTOPPABLE},
+	}
+	if fmt.Sprint(entry.Names) != fmt.Sprint(want) || fmt.Sprint(cursors) != "[ a.crypto]" {
+		t.Errorf("Names = %+v after cursors %q, want %+v", entry.Names, cursors, want)
+	}
+}
+
+func TestUnstoppableNeedsKey(t *testing.T) {
+	registered := func(f *fetcher) int {
+		n := 0
+		for _, src := range f.enabledIdentitySources() {
+			if src.Name() == UNSTOPPABLE {
+				n++
+			}
+		}
+		return n
+	}
+	if registered(NewFetcher()) != 0 {
+		t.Error("Unstoppable Domains registered without an API key")
+	}
+	if registered(NewFetcher(WithAPIKey(UNSTOPPABLE, "test-key"))) != 1 {
+		t.Error("Unstoppable Domains not registered with an API key")
+	}
+
+	// a source given under the same name replaces the built-in one rather than running next to it
+	custom := NewIdentitySource(UNSTOPPABLE, func(ctx context.Context, address string) IdentityEntry { return IdentityEntry{} })
+	if n := registered(NewFetcher(WithAPIKey(UNSTOPPABLE, "test-key"), WithIdentitySource(custom))); n != 1 {
+		t.Errorf("%d identity sources named %s, want the custom one only", n, UNSTOPPABLE)
+	}
+}
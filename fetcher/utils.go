@@ -2,31 +2,104 @@ package fetcher
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"regexp"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 )
 
 type RequestArgs struct {
+	// source is the data source the request is made for, it selects the per-source options
+	source string
 	url    string
 	method string
 	params map[string]string
 	header map[string]string
 	body   []byte
+	// idempotent marks a POST that only reads data, e.g. a GraphQL query, so it may be retried
+	idempotent bool
 }
 
-func sendRequest(client *http.Client, args RequestArgs) ([]byte, error) {
+// sendRequest applies the per-source options of args.source and the fetcher-wide ones before performing the request
+func (f *fetcher) sendRequest(ctx context.Context, args RequestArgs) ([]byte, error) {
+	if cfg, ok := f.sourceConfigs[args.source]; ok {
+		if cfg.baseURL != "" {
+			rebased, err := rebaseURL(args.url, cfg.baseURL)
+			if err != nil {
+				return nil, err
+			}
+			args.url = rebased
+		}
+		if len(cfg.header) > 0 {
+			header := make(map[string]string, len(args.header)+len(cfg.header))
+			for k, v := range args.header {
+				header[k] = v
+			}
+			for k, v := range cfg.header {
+				header[k] = v
+			}
+			args.header = header
+		}
+	}
+	if f.userAgent != "" {
+		header := map[string]string{"User-Agent": f.userAgent}
+		for k, v := range args.header {
+			header[k] = v
+		}
+		args.header = header
+	}
+	return f.sendWithRetry(ctx, args)
+}
+
+// graphQLQuery posts a GraphQL query with its variables to the API of source at url and decodes its data into out
+func (f *fetcher) graphQLQuery(ctx context.Context, source, url, query string, variables map[string]interface{}, out interface{}) error {
+	jsonQuery, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	body, err := f.sendRequest(ctx, RequestArgs{
+		source: source,
+		url:    url,
+		method: "POST",
+		body:   jsonQuery,
+		// GraphQL queries only read data
+		idempotent: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	var resp GraphQLResp
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return errors.New(source + " graphql: " + resp.Errors[0].Message)
+	}
+	return json.Unmarshal(resp.Data, out)
+}
+
+// sendRequest performs the request described by args, the request is bound to ctx
+// so cancellation and deadlines abort the outbound call
+func sendRequest(ctx context.Context, client *http.Client, args RequestArgs) ([]byte, error) {
 	var req *http.Request
 	var err error
 
 	switch args.method {
 	case "GET":
-		req, err = http.NewRequest(args.method, args.url, nil)
+		req, err = http.NewRequestWithContext(ctx, args.method, args.url, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -40,7 +113,7 @@ func sendRequest(client *http.Client, args RequestArgs) ([]byte, error) {
 		req.URL.RawQuery = query.Encode()
 
 	case "POST":
-		req, err = http.NewRequest(args.method, args.url, bytes.NewBuffer(args.body))
+		req, err = http.NewRequestWithContext(ctx, args.method, args.url, bytes.NewBuffer(args.body))
 		if err != nil {
 			return nil, err
 		}
@@ -58,8 +131,13 @@ func sendRequest(client *http.Client, args RequestArgs) ([]byte, error) {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	recordHTTPStatus(ctx, resp.StatusCode)
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Response code: %d", resp.StatusCode)
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode,
+			URL:        args.url,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
 	respBody, err := ioutil.ReadAll(resp.Body)
@@ -123,12 +201,13 @@ func convertTwitterHandle(inputHandle string) string {
 	}
 
 	// Solution 3 - some inputs begin with "/"
-	if retHandle[0] == '/' {
+	// the handles come from user-entered profile fields, any of these steps may leave nothing
+	if strings.HasPrefix(retHandle, "/") {
 		retHandle = retHandle[1:]
 	}
 
 	// Solution 4 - some inputs ends with "/"
-	if retHandle[len(retHandle)-1] == '/' {
+	if strings.HasSuffix(retHandle, "/") {
 		retHandle = retHandle[:len(retHandle)-1]
 	}
 
@@ -0,0 +1,68 @@
+package fetcher
+
+import "testing"
+
+func TestConvertTwitterHandle(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://twitter.com/BrantlyMillegan", "BrantlyMillegan"},
+		{"https://twitter/BrantlyMillegan", "BrantlyMillegan"},
+		{"www.twitter.com/BrantlyMillegan/", "BrantlyMillegan"},
+		{"@BrantlyMillegan", "BrantlyMillegan"},
+		{"/BrantlyMillegan", "BrantlyMillegan"},
+		{"BrantlyMillegan", "BrantlyMillegan"},
+		{"/", ""},
+		{"@/", ""},
+		{"//", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := convertTwitterHandle(tt.in); got != tt.want {
+			t.Errorf("convertTwitterHandle(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAddressFilter(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"0x983110309620d911731ac0932219af06091b6744", true},
+		{"983110309620d911731ac0932219af06091b6744", true},
+		{"brantly.eth", true},
+		{".eth", false},
+		{"0x9831", false},
+		{"brantly", false},
+		{"brad.crypto", true},
+		{"brantly.nft", true},
+		{"Brantly.X", true},
+		{"sub.brantly.wallet", true},
+		{".crypto", false},
+		{"brantly.com", false},
+		{"fid:3", true},
+		{"fid:", false},
+		{"fid:0x3", false},
+	}
+	for _, tt := range tests {
+		if got := addressFilter(tt.in); got != tt.want {
+			t.Errorf("addressFilter(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRebaseURL(t *testing.T) {
+	tests := []struct {
+		raw, base, want string
+	}{
+		{"https://api.opensea.io/api/v1/assets?owner=0x1", "http://127.0.0.1:8080", "http://127.0.0.1:8080/api/v1/assets?owner=0x1"},
+		{"https://context.app/api/profile/0x1", "https://mirror.internal/context/", "https://mirror.internal/context/api/profile/0x1"},
+	}
+	for _, tt := range tests {
+		got, err := rebaseURL(tt.raw, tt.base)
+		if err != nil || got != tt.want {
+			t.Errorf("rebaseURL(%q, %q) = %q, %v, want %q", tt.raw, tt.base, got, err, tt.want)
+		}
+	}
+}
@@ -9,4 +9,5 @@ require (
 	github.com/ryboe/q v1.0.15 // indirect
 	github.com/wealdtech/go-ens/v3 v3.5.1
 	go.uber.org/zap v1.19.1
+	golang.org/x/time v0.0.0-20210723032227-1f47c861a9ac
 )
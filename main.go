@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/cyberconnecthq/indexer/fetcher"
 )
@@ -12,7 +13,12 @@ const (
 )
 
 func main() {
-	f := fetcher.NewFetcher()
+	// ENS names such as brantly.eth are accepted as input once an Ethereum JSON-RPC endpoint is set
+	var opts []fetcher.Option
+	if rpcURL := os.Getenv("ETH_RPC_URL"); rpcURL != "" {
+		opts = append(opts, fetcher.WithEthRPC(rpcURL))
+	}
+	f := fetcher.NewFetcher(opts...)
 
 	ids, err := f.FetchIdentity(address)
 	if err != nil {